@@ -0,0 +1,92 @@
+package play
+
+import (
+	"encoding/json/jsontext"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+var ErrNotAllowed = errors.New("value not allowed")
+
+// NotAllowedError is returned by decoding with WithAllowedValues.
+// errors.Is(err, ErrNotAllowed) reports true for it.
+type NotAllowedError struct {
+	Pointer jsontext.Pointer
+	Value   string
+	Allowed []string
+}
+
+func (e *NotAllowedError) Error() string {
+	return fmt.Sprintf("%s: value = %q, allowed = %q, pointer = %q", ErrNotAllowed, e.Value, e.Allowed, e.Pointer)
+}
+
+func (e *NotAllowedError) Is(target error) bool {
+	return target == ErrNotAllowed
+}
+
+// WithAllowedValues rejects a string value at pointer not contained in allowed with *NotAllowedError.
+// A "*" token in pointer matches any token, e.g. "/items/*/status".
+// Values of other kinds are left to the target type.
+func WithAllowedValues(pointer jsontext.Pointer, allowed []string) DecodeOption {
+	return func(c *decodeConfig) {
+		c.hooks = append(c.hooks, func(dec *jsontext.Decoder, tok jsontext.Token, emit func(jsontext.Token) error) error {
+			if tok.Kind() == '"' && !justReadName(dec) && matchPointer(pointer, dec.StackPointer()) {
+				if v := tok.String(); !slices.Contains(allowed, v) {
+					return &NotAllowedError{Pointer: dec.StackPointer(), Value: v, Allowed: allowed}
+				}
+			}
+			return emit(tok)
+		})
+	}
+}
+
+func TestDecodeOption_AllowedValues(t *testing.T) {
+	type item struct {
+		Status string `json:"status"`
+	}
+	type sample struct {
+		Level string `json:"level"`
+		Items []item `json:"items"`
+	}
+
+	opts := []DecodeOption{
+		WithAllowedValues("/level", []string{"debug", "info", "warn", "error"}),
+		WithAllowedValues("/items/*/status", []string{"open", "closed"}),
+	}
+
+	var s sample
+	err := UnmarshalWith([]byte(`{"level":"info","items":[{"status":"open"},{"status":"closed"}]}`), &s, opts...)
+	if err != nil {
+		panic(err)
+	}
+	if s.Level != "info" || len(s.Items) != 2 {
+		t.Errorf("incorrect: %#v", s)
+	}
+
+	type testCase struct {
+		in       string
+		expected NotAllowedError
+	}
+	for _, tc := range []testCase{
+		{`{"level":"verbose"}`, NotAllowedError{"/level", "verbose", []string{"debug", "info", "warn", "error"}}},
+		{`{"level":"warn","items":[{"status":"open"},{"status":"pending"}]}`, NotAllowedError{"/items/1/status", "pending", []string{"open", "closed"}}},
+	} {
+		t.Run(tc.in, func(t *testing.T) {
+			var s sample
+			err := UnmarshalWith([]byte(tc.in), &s, opts...)
+			if !errors.Is(err, ErrNotAllowed) {
+				t.Fatalf("should be ErrNotAllowed, but is %v", err)
+			}
+			var naErr *NotAllowedError
+			if !errors.As(err, &naErr) {
+				t.Fatalf("should be *NotAllowedError, but is %T", err)
+			}
+			if naErr.Pointer != tc.expected.Pointer || naErr.Value != tc.expected.Value || !slices.Equal(naErr.Allowed, tc.expected.Allowed) {
+				t.Errorf("not equal: expected(%#v) != actual(%#v)", tc.expected, *naErr)
+			}
+			t.Logf("err = %v", err)
+		})
+	}
+}
@@ -1,9 +1,15 @@
 package play
 
 import (
+	"encoding"
 	"encoding/json/jsontext"
 	"encoding/json/v2"
+	"errors"
 	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
 )
 
@@ -70,40 +76,179 @@ func (e Either[L, R]) MapRight(mapper func(l R) R) Either[L, R] {
 	return MapRight(e, mapper)
 }
 
+// MapBothErr maps the left value with fl or the right value with fr, whichever is set,
+// returning the error of the mapper that ran.
+func MapBothErr[L, R, L2, R2 any](e Either[L, R], fl func(L) (L2, error), fr func(R) (R2, error)) (Either[L2, R2], error) {
+	if e.IsLeft() {
+		l, err := fl(e.Left())
+		if err != nil {
+			return Either[L2, R2]{}, err
+		}
+		return Left[L2, R2](l), nil
+	}
+	r, err := fr(e.Right())
+	if err != nil {
+		return Either[L2, R2]{}, err
+	}
+	return Right[L2](r), nil
+}
+
+// FilterLeft converts a left value failing pred into a right value produced by onFail.
+// A left value passing pred and a right value are returned as is.
+func (e Either[L, R]) FilterLeft(pred func(L) bool, onFail func(L) R) Either[L, R] {
+	if e.IsLeft() && !pred(e.Left()) {
+		return Right[L](onFail(e.Left()))
+	}
+	return e
+}
+
 func (e Either[L, R]) MarshalJSONTo(enc *jsontext.Encoder) error {
+	// Passing pointers rather than values avoids copying a possibly large branch into an interface,
+	// and lets the branch use MarshalJSONTo with pointer receiver.
+	// Either way the branch writes directly to enc, thus streams.
 	if e.IsLeft() {
-		return json.MarshalEncode(enc, e.Left())
+		return json.MarshalEncode(enc, &e.l)
 	}
-	return json.MarshalEncode(enc, e.Right())
+	return json.MarshalEncode(enc, &e.r)
 }
 
 func (e *Either[L, R]) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	return e.unmarshal(dec, false)
+}
+
+// unmarshal decodes a value into either branch. rightFirst tells which branch is tried first
+// when both may accept the value.
+func (e *Either[L, R]) unmarshal(dec *jsontext.Decoder, rightFirst bool) error {
+	if kinds := eitherKindsFor[L, R](); kinds.disjoint && !hasArshalOptions(dec.Options()) {
+		// L and R accept no common kind; the next kind alone tells which branch to decode.
+		// Other kinds, including null which both accept, go to the try-both path.
+		switch k := byte(dec.PeekKind()); {
+		case strings.IndexByte(kinds.l, k) >= 0:
+			var l L
+			if err := json.UnmarshalDecode(dec, &l); err != nil {
+				return fmt.Errorf("Either[L, R]: unmarshal failed for L: %w", err)
+			}
+			e.isRight = false
+			e.l = l
+			e.r = *new(R)
+			return nil
+		case strings.IndexByte(kinds.r, k) >= 0:
+			var r R
+			if err := json.UnmarshalDecode(dec, &r); err != nil {
+				return fmt.Errorf("Either[L, R]: unmarshal failed for R: %w", err)
+			}
+			e.isRight = true
+			e.l = *new(L)
+			e.r = r
+			return nil
+		}
+	}
+	return e.unmarshalTryBoth(dec, rightFirst)
+}
+
+// unmarshalTryBoth buffers the value and decodes it into L, and then into R if L fails.
+// If rightFirst is true, R is tried first instead.
+func (e *Either[L, R]) unmarshalTryBoth(dec *jsontext.Decoder, rightFirst bool) error {
 	val, err := dec.ReadValue()
 	if err != nil {
 		return err
 	}
 
-	var l L
-	errL := json.Unmarshal(val, &l, dec.Options())
-	if errL == nil {
-		e.isRight = false
-		e.l = l
-		e.r = *new(R)
-		return nil
+	var (
+		l          L
+		r          R
+		errL, errR error
+	)
+	tryL := func() bool {
+		errL = json.Unmarshal(val, &l, dec.Options())
+		if errL == nil {
+			e.isRight = false
+			e.l = l
+			e.r = *new(R)
+		}
+		return errL == nil
 	}
-
-	var r R
-	errR := json.Unmarshal(val, &r, dec.Options())
-	if errR == nil {
-		e.isRight = true
-		e.l = *new(L)
-		e.r = r
+	tryR := func() bool {
+		errR = json.Unmarshal(val, &r, dec.Options())
+		if errR == nil {
+			e.isRight = true
+			e.l = *new(L)
+			e.r = r
+		}
+		return errR == nil
+	}
+	if rightFirst {
+		if tryR() || tryL() {
+			return nil
+		}
+	} else if tryL() || tryR() {
 		return nil
 	}
 
 	return fmt.Errorf("Either[L, R]: unmarshal failed for both L and R: l = (%w), r = (%w)", errL, errR)
 }
 
+type eitherKinds struct {
+	// kinds other than null accepted by L and R, or "" if not a plain scalar.
+	l, r     string
+	disjoint bool
+}
+
+// eitherKindsCache caches eitherKinds keyed by reflect.Type of Either[L, R].
+var eitherKindsCache sync.Map
+
+func eitherKindsFor[L, R any]() eitherKinds {
+	key := reflect.TypeFor[Either[L, R]]()
+	if k, ok := eitherKindsCache.Load(key); ok {
+		return k.(eitherKinds)
+	}
+	l, r := scalarKinds(reflect.TypeFor[L]()), scalarKinds(reflect.TypeFor[R]())
+	k := eitherKinds{
+		l:        l,
+		r:        r,
+		disjoint: l != "" && r != "" && !strings.ContainsAny(l, r),
+	}
+	eitherKindsCache.Store(key, k)
+	return k
+}
+
+var customUnmarshalerTypes = []reflect.Type{
+	reflect.TypeFor[json.UnmarshalerFrom](),
+	reflect.TypeFor[json.Unmarshaler](),
+	reflect.TypeFor[encoding.TextUnmarshaler](),
+}
+
+// scalarKinds returns kinds of JSON values t accepts by default other than null,
+// or "" if t is not a plain scalar, e.g. an interface, a composite or a type with custom unmarshal methods.
+func scalarKinds(t reflect.Type) string {
+	for _, it := range customUnmarshalerTypes {
+		if reflect.PointerTo(t).Implements(it) {
+			return ""
+		}
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return "tf"
+	case reflect.String:
+		return `"`
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return "0"
+	}
+	return ""
+}
+
+// hasArshalOptions reports whether opts may change kinds a type accepts,
+// in which case kinds computed by scalarKinds are not reliable.
+func hasArshalOptions(opts json.Options) bool {
+	if v, ok := json.GetOption(opts, json.StringifyNumbers); ok && v {
+		return true
+	}
+	_, ok := json.GetOption(opts, json.WithUnmarshalers)
+	return ok
+}
+
 func TestArshalerEither(t *testing.T) {
 	type testCase struct {
 		in   string
@@ -121,9 +266,139 @@ func TestArshalerEither(t *testing.T) {
 		}
 		t.Logf("err = %v", err)
 		/*
-		   arshaler_either_test.go:122: err = <nil>
-		   arshaler_either_test.go:122: err = <nil>
-		   arshaler_either_test.go:122: err = json: cannot unmarshal into Go play.Either[string,int]: Either[L, R]: unmarshal failed for both L and R: l = (json: cannot unmarshal JSON boolean into Go string), r = (json: cannot unmarshal JSON boolean into Go int)
+		   arshaler_either_test.go:247: err = <nil>
+		   arshaler_either_test.go:247: err = <nil>
+		   arshaler_either_test.go:247: err = json: cannot unmarshal into Go play.Either[string,int]: Either[L, R]: unmarshal failed for both L and R: l = (json: cannot unmarshal JSON boolean into Go string), r = (json: cannot unmarshal JSON boolean into Go int)
 		*/
 	}
 }
+
+func TestEitherMapBothErr(t *testing.T) {
+	parseInt := func(s string) (int, error) { return strconv.Atoi(s) }
+	format := func(i int) (string, error) { return strconv.Itoa(i * 2), nil }
+
+	e, err := MapBothErr(Left[string, int]("foo"), parseInt, format)
+	if err == nil {
+		t.Errorf("should cause an error")
+	}
+	t.Logf("e = %#v, err = %v", e, err)
+	// arshaler_either_test.go:284: e = play.Either[int,string]{isRight:false, l:0, r:""}, err = strconv.Atoi: parsing "foo": invalid syntax
+
+	e, err = MapBothErr(Left[string, int]("12"), parseInt, format)
+	if err != nil {
+		panic(err)
+	}
+	if !e.IsLeft() || e.Left() != 12 {
+		t.Errorf("incorrect: %#v", e)
+	}
+
+	e, err = MapBothErr(Right[string](21), parseInt, format)
+	if err != nil {
+		panic(err)
+	}
+	if !e.IsRight() || e.Right() != "42" {
+		t.Errorf("incorrect: %#v", e)
+	}
+}
+
+func TestEitherFilterLeft(t *testing.T) {
+	nonEmpty := func(s string) bool { return s != "" }
+	onFail := func(s string) error { return errors.New("empty name") }
+
+	type testCase struct {
+		in       Either[string, error]
+		expected Either[string, error]
+	}
+	errOrig := errors.New("original")
+	for _, tc := range []testCase{
+		{Left[string, error]("foo"), Left[string, error]("foo")},
+		{Left[string, error](""), Right[string](errors.New("empty name"))},
+		{Right[string](errOrig), Right[string](errOrig)},
+	} {
+		e := tc.in.FilterLeft(nonEmpty, onFail)
+		if e.IsLeft() != tc.expected.IsLeft() || e.Left() != tc.expected.Left() {
+			t.Errorf("not equal: expected(%#v) != actual(%#v)", tc.expected, e)
+		}
+		if e.IsRight() && e.Right().Error() != tc.expected.Right().Error() {
+			t.Errorf("not equal: expected(%v) != actual(%v)", tc.expected.Right(), e.Right())
+		}
+	}
+}
+
+func TestArshalerEither_fastPath(t *testing.T) {
+	if k := eitherKindsFor[string, int](); !k.disjoint {
+		t.Errorf("string and int should be disjoint: %#v", k)
+	}
+	for _, k := range []eitherKinds{
+		eitherKindsFor[int, float64](),
+		eitherKindsFor[string, any](),
+		eitherKindsFor[string, []int](),
+		eitherKindsFor[Option[int], string](),
+	} {
+		if k.disjoint {
+			t.Errorf("should not be disjoint: %#v", k)
+		}
+	}
+
+	type testCase struct {
+		in       string
+		opts     []json.Options
+		expected Either[int, string]
+	}
+	for _, tc := range []testCase{
+		{`123`, nil, Left[int, string](123)},
+		{`"123"`, nil, Right[int]("123")},
+		// null is accepted by both, thus left wins as try-both does.
+		{`null`, nil, Left[int, string](0)},
+		// StringifyNumbers lets int accept strings; fast path is off.
+		{`"123"`, []json.Options{json.StringifyNumbers(true)}, Left[int, string](123)},
+	} {
+		t.Run(tc.in, func(t *testing.T) {
+			var e Either[int, string]
+			err := json.Unmarshal([]byte(tc.in), &e, tc.opts...)
+			if err != nil {
+				panic(err)
+			}
+			if e != tc.expected {
+				t.Errorf("not equal: expected(%#v) != actual(%#v)", tc.expected, e)
+			}
+		})
+	}
+
+	var e Either[int, string]
+	err := json.Unmarshal([]byte(`1.5`), &e)
+	if err == nil {
+		t.Errorf("should cause an error")
+	}
+	t.Logf("err = %v", err)
+	// arshaler_either_test.go:373: err = json: cannot unmarshal into Go play.Either[int,string]: Either[L, R]: unmarshal failed for L: json: cannot unmarshal JSON number 1.5 into Go int: invalid syntax
+}
+
+// tryBothEither always takes the try-both path.
+type tryBothEither[L, R any] struct {
+	Either[L, R]
+}
+
+func (e *tryBothEither[L, R]) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	return e.unmarshalTryBoth(dec, false)
+}
+
+func BenchmarkArshalerEither(b *testing.B) {
+	input := []byte(`["foo",1,"bar",2,"baz",3,"qux",4]`)
+	b.Run("fast path", func(b *testing.B) {
+		for b.Loop() {
+			var s []Either[string, int]
+			if err := json.Unmarshal(input, &s); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("try both", func(b *testing.B) {
+		for b.Loop() {
+			var s []tryBothEither[string, int]
+			if err := json.Unmarshal(input, &s); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
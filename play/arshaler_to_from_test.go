@@ -3,6 +3,8 @@ package play
 import (
 	"encoding/json/jsontext"
 	"encoding/json/v2"
+	"errors"
+	"fmt"
 	"testing"
 )
 
@@ -13,6 +15,12 @@ var (
 	_ json.UnmarshalerFrom = (*Und[any])(nil)
 )
 
+// ErrNoneMapKey is returned when marshaling a map whose key is a None Option.
+//
+// A Some key is written as its inner value, which json v2 quotes as a name:
+// strings as is, numbers and bools stringified, and encoding.TextMarshaler types as their text.
+var ErrNoneMapKey = errors.New("None Option used as map key")
+
 type Option[V any] struct {
 	some bool
 	v    V
@@ -44,6 +52,10 @@ func (o Option[V]) Value() V {
 
 func (o Option[V]) MarshalJSONTo(enc *jsontext.Encoder) error {
 	if o.IsNone() {
+		// null can not be an object name.
+		if kind, length := enc.StackIndex(enc.StackDepth()); kind == '{' && length%2 == 0 {
+			return ErrNoneMapKey
+		}
 		return enc.WriteToken(jsontext.Null)
 	}
 	return json.MarshalEncode(enc, o.Value())
@@ -71,6 +83,9 @@ func (o *Option[V]) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
 	return nil
 }
 
+// ErrUndMapKey is returned when marshaling a map whose key is an undefined or null Und.
+var ErrUndMapKey = errors.New("undefined or null Und used as map key")
+
 type Und[V any] struct {
 	opt Option[Option[V]]
 }
@@ -107,11 +122,33 @@ func (u Und[V]) Value() V {
 	return u.opt.Value().Value()
 }
 
+// MarshalJSONTo writes undefined and null depending on where the encoder is:
+//   - As an array element, undefined is written as null, since an element can not be omitted without shifting later elements.
+//     This diverges from struct fields, where undefined is expected to be omitted by omitzero before reaching here.
+//   - As an object member value, the name is already written (e.g. map values; see MarshalMapUnd), so undefined is written as null.
+//   - As an object name, neither undefined nor null can be written, and an error wrapping ErrUndMapKey is returned.
+//   - At top level, undefined is written as null; use MarshalUnd to encode it as empty output.
+//
+// Use UndStrict to fail wherever undefined would become null.
 func (u Und[V]) MarshalJSONTo(enc *jsontext.Encoder) error {
-	if !u.IsDefined() {
+	if u.IsDefined() {
+		return json.MarshalEncode(enc, u.Value())
+	}
+	kind, length := enc.StackIndex(enc.StackDepth())
+	switch {
+	case kind == '{' && length%2 == 0:
+		state := "null"
+		if u.IsUndefined() {
+			state = "undefined"
+		}
+		return fmt.Errorf("%w: %s", ErrUndMapKey, state)
+	case kind == '[':
+		// an element can not be omitted without shifting later elements.
+		return enc.WriteToken(jsontext.Null)
+	default:
+		// the member name is already written, or at top level.
 		return enc.WriteToken(jsontext.Null)
 	}
-	return json.MarshalEncode(enc, u.Value())
 }
 
 func (u *Und[V]) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
@@ -172,3 +209,53 @@ func TestArshalerToFrom(t *testing.T) {
 		})
 	}
 }
+
+func TestUndContext(t *testing.T) {
+	arr := []Und[int]{Null[int](), Defined(1), Undefined[int]()}
+	bin, err := json.Marshal(arr)
+	if err != nil {
+		panic(err)
+	}
+	expected := `[null,1,null]`
+	if string(bin) != expected {
+		t.Errorf("not equal: expected(%s) != actual(%s)", expected, string(bin))
+	}
+	var unmarshaled []Und[int]
+	err = json.Unmarshal(bin, &unmarshaled)
+	if err != nil {
+		panic(err)
+	}
+	// undefined in an array does not survive round trip.
+	if len(unmarshaled) != 3 || !unmarshaled[0].IsNull() || unmarshaled[1] != Defined(1) || !unmarshaled[2].IsNull() {
+		t.Errorf("incorrect: %#v", unmarshaled)
+	}
+
+	type sample struct {
+		Foo Und[int] `json:",omitzero"`
+		Bar Und[int]
+		Baz []Und[int] `json:",omitzero"`
+	}
+	bin, err = json.Marshal(sample{Baz: []Und[int]{Undefined[int]()}})
+	if err != nil {
+		panic(err)
+	}
+	expected = `{"Bar":null,"Baz":[null]}`
+	if string(bin) != expected {
+		t.Errorf("not equal: expected(%s) != actual(%s)", expected, string(bin))
+	}
+
+	bin, err = json.Marshal(map[Und[string]]int{Defined("foo"): 1})
+	if err != nil {
+		panic(err)
+	}
+	expected = `{"foo":1}`
+	if string(bin) != expected {
+		t.Errorf("not equal: expected(%s) != actual(%s)", expected, string(bin))
+	}
+	for _, key := range []Und[string]{Undefined[string](), Null[string]()} {
+		_, err = json.Marshal(map[Und[string]]int{key: 1})
+		if !errors.Is(err, ErrUndMapKey) {
+			t.Errorf("should be ErrUndMapKey, but is %v", err)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package play
+
+import (
+	"encoding/json/jsontext"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// Node is a JSON value built by BuildTree.
+type Node struct {
+	Kind jsontext.Kind
+	// Name is the object member name of the node if its parent is an object.
+	Name string
+	// Value is the raw literal of a scalar node. It is nil for objects and arrays.
+	Value jsontext.Value
+	// Children are elements or members of an array or object, in input order.
+	Children []*Node
+}
+
+// BuildTree reads a value from dec into a tree of Node.
+// Nesting deeper than maxDepth, counted as MaxDepth does, is an error wrapping ErrMaxDepth.
+//
+// The tree is built with an explicit stack rather than recursion,
+// so that a deep input can not exhaust the goroutine stack.
+// It is not used by other features of this package, which stream tokens rather than build trees.
+func BuildTree(dec *jsontext.Decoder, maxDepth int) (Node, error) {
+	var (
+		root  *Node
+		stack []*Node
+	)
+	attach := func(n *Node) {
+		if len(stack) == 0 {
+			root = n
+			return
+		}
+		parent := stack[len(stack)-1]
+		parent.Children = append(parent.Children, n)
+	}
+
+	for {
+		var name string
+		if len(stack) > 0 && stack[len(stack)-1].Kind == '{' && dec.PeekKind() != '}' {
+			tok, err := dec.ReadToken()
+			if err != nil {
+				return Node{}, err
+			}
+			name = tok.String()
+		}
+
+		switch k := dec.PeekKind(); k {
+		case 0:
+			// let ReadToken report the error.
+			_, err := dec.ReadToken()
+			return Node{}, err
+		case '}', ']':
+			if _, err := dec.ReadToken(); err != nil {
+				return Node{}, err
+			}
+			stack = stack[:len(stack)-1]
+		case '{', '[':
+			if len(stack) >= maxDepth {
+				return Node{}, fmt.Errorf("%w: limit = %d, at %q", ErrMaxDepth, maxDepth, dec.StackPointer())
+			}
+			if _, err := dec.ReadToken(); err != nil {
+				return Node{}, err
+			}
+			n := &Node{Kind: k, Name: name}
+			attach(n)
+			stack = append(stack, n)
+		default:
+			val, err := dec.ReadValue()
+			if err != nil {
+				return Node{}, err
+			}
+			attach(&Node{Kind: k, Name: name, Value: val.Clone()})
+		}
+
+		if len(stack) == 0 {
+			return *root, nil
+		}
+	}
+}
+
+func TestBuildTree(t *testing.T) {
+	root, err := BuildTree(jsontext.NewDecoder(strings.NewReader(decoderTestInput)), 4)
+	if err != nil {
+		panic(err)
+	}
+
+	// flatten the tree into pre-order lines to compare.
+	var lines []string
+	var visit func(n *Node, indent string)
+	visit = func(n *Node, indent string) {
+		lines = append(lines, fmt.Sprintf("%s%s %q %s", indent, n.Kind, n.Name, string(n.Value)))
+		for _, c := range n.Children {
+			visit(c, indent+"  ")
+		}
+	}
+	visit(&root, "")
+
+	expected := []string{
+		`{ "" `,
+		`  null "foo" null`,
+		`  [ "baz" `,
+		`    string "" "qux"`,
+		`    number "" 123`,
+		`    string "" "quux"`,
+		`    [ "" `,
+		`      { "" `,
+		`        null "corge" null`,
+	}
+	if strings.Join(lines, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("not equal:\nexpected:\n%s\nactual:\n%s", strings.Join(expected, "\n"), strings.Join(lines, "\n"))
+	}
+
+	_, err = BuildTree(jsontext.NewDecoder(strings.NewReader(decoderTestInput)), 3)
+	if !errors.Is(err, ErrMaxDepth) {
+		t.Errorf("should be ErrMaxDepth, but is %v", err)
+	}
+	t.Logf("err = %v", err)
+	// build_tree_test.go:123: err = max depth exceeded: limit = 3, at "/baz/3"
+
+	root, err = BuildTree(jsontext.NewDecoder(strings.NewReader(`"foo"`)), 0)
+	if err != nil {
+		panic(err)
+	}
+	if root.Kind != '"' || string(root.Value) != `"foo"` {
+		t.Errorf("incorrect: %#v", root)
+	}
+
+	// jsontext itself limits nesting to 10000.
+	const deep = 5000
+	input := strings.Repeat("[", deep) + strings.Repeat("]", deep)
+	_, err = BuildTree(jsontext.NewDecoder(strings.NewReader(input)), deep)
+	if err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,112 @@
+package play
+
+import (
+	"crypto/sha256"
+	"encoding/json/jsontext"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// Cardinality reads the array at pointer in the value read from dec and returns the number of distinct elements in it.
+// Elements are compared by their canonical form (RFC 8785), so `1.0` and `1`, or objects differing only in member order, are the same.
+// As UniqueValues does, only the SHA-256 hash of the canonical form of each element is kept, not elements themselves.
+//
+// A non-array value at pointer is an error. A missing pointer is an error wrapping ErrNotFound.
+func Cardinality(dec *jsontext.Decoder, pointer jsontext.Pointer) (int, error) {
+	var n int
+	count := func(dec *jsontext.Decoder) (err error) {
+		n, err = countDistinct(dec)
+		return err
+	}
+	var err error
+	if pointer == "" {
+		err = count(dec)
+	} else {
+		err = ReadJSONAt(dec, pointer, count)
+	}
+	if errors.Is(err, ErrNotFound) {
+		err = fmt.Errorf("cardinality: %q: %w", pointer, err)
+	}
+	return n, err
+}
+
+func countDistinct(dec *jsontext.Decoder) (int, error) {
+	if k := dec.PeekKind(); k != '[' {
+		return 0, fmt.Errorf("cardinality: expected [ but is %s", k)
+	}
+	if _, err := dec.ReadToken(); err != nil {
+		return 0, err
+	}
+	seen := make(map[[sha256.Size]byte]struct{})
+	for dec.PeekKind() != ']' {
+		val, err := dec.ReadValue()
+		if err != nil {
+			return 0, err
+		}
+		h, err := canonicalHash(val)
+		if err != nil {
+			return 0, err
+		}
+		seen[h] = struct{}{}
+	}
+	if _, err := dec.ReadToken(); err != nil {
+		return 0, err
+	}
+	return len(seen), nil
+}
+
+func TestCardinality(t *testing.T) {
+	const input = `{
+    "events": [
+        {"user": "alice", "n": 1},
+        {"n": 1, "user": "alice"},
+        {"user": "bob", "n": 1.0},
+        {"user": "alice", "n": 2}
+    ],
+    "users": ["alice", "bob", "alice", "carol", "bob", "alice"],
+    "ids": [1, 1.0, 10e-1, 2, "1", 1e21, 1000000000000000000000],
+    "empty": [],
+    "name": "foo"
+}`
+
+	type testCase struct {
+		pointer  jsontext.Pointer
+		expected int
+	}
+	for _, tc := range []testCase{
+		{"/users", 3},
+		{"/events", 3},
+		{"/ids", 4},
+		{"/empty", 0},
+	} {
+		t.Run(string(tc.pointer), func(t *testing.T) {
+			n, err := Cardinality(jsontext.NewDecoder(strings.NewReader(input)), tc.pointer)
+			if err != nil {
+				panic(err)
+			}
+			if n != tc.expected {
+				t.Errorf("not equal: expected(%d) != actual(%d)", tc.expected, n)
+			}
+		})
+	}
+
+	n, err := Cardinality(jsontext.NewDecoder(strings.NewReader(`[1,2,2,[3],[3]]`)), "")
+	if err != nil {
+		panic(err)
+	}
+	if n != 3 {
+		t.Errorf("not equal: expected(%d) != actual(%d)", 3, n)
+	}
+
+	_, err = Cardinality(jsontext.NewDecoder(strings.NewReader(input)), "/name")
+	if err == nil {
+		t.Errorf("should cause an error for non array")
+	}
+	t.Logf("err = %v", err)
+	_, err = Cardinality(jsontext.NewDecoder(strings.NewReader(input)), "/nope")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("should be ErrNotFound, but is %v", err)
+	}
+}
@@ -0,0 +1,265 @@
+package play
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json/jsontext"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var ErrCompactFormat = errors.New("invalid compact format")
+
+// Tags of the compact format written by EncodeCompact.
+//
+// A value is a tag byte optionally followed by a payload:
+//
+//	0x00           null
+//	0x01           false
+//	0x02           true
+//	0x03 len data  string; len is uvarint byte length of UTF-8 data. Object names are strings too.
+//	0x04 len data  number; data is the number literal as written in JSON, to keep its precision.
+//	0x05 n         integer; n is a zig-zag varint, used for integer literals fitting in int64.
+//	0x06           begin object; followed by alternating names and values
+//	0x07           end object
+//	0x08           begin array; followed by values
+//	0x09           end array
+//
+// The format is stable: tags are never renumbered, and new ones are only appended.
+const (
+	compactNull byte = iota
+	compactFalse
+	compactTrue
+	compactString
+	compactNumber
+	compactInt
+	compactBeginObject
+	compactEndObject
+	compactBeginArray
+	compactEndArray
+)
+
+// EncodeCompact reads a value from dec and writes it to w in the compact binary format described at compactNull.
+// It is the counterpart of DecodeCompact.
+func EncodeCompact(dec *jsontext.Decoder, w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	var buf [binary.MaxVarintLen64 + 1]byte
+	writeBytes := func(tag byte, data string) error {
+		n := binary.PutUvarint(buf[1:], uint64(len(data)))
+		buf[0] = tag
+		if _, err := bw.Write(buf[:1+n]); err != nil {
+			return err
+		}
+		_, err := bw.WriteString(data)
+		return err
+	}
+
+	depth := dec.StackDepth()
+	for {
+		tok, err := dec.ReadToken()
+		if err != nil {
+			return err
+		}
+		switch tok.Kind() {
+		case 'n':
+			err = bw.WriteByte(compactNull)
+		case 'f':
+			err = bw.WriteByte(compactFalse)
+		case 't':
+			err = bw.WriteByte(compactTrue)
+		case '"':
+			err = writeBytes(compactString, tok.String())
+		case '0':
+			lit := tok.String()
+			if i, perr := strconv.ParseInt(lit, 10, 64); perr == nil && strconv.FormatInt(i, 10) == lit {
+				buf[0] = compactInt
+				n := binary.PutVarint(buf[1:], i)
+				_, err = bw.Write(buf[:1+n])
+			} else {
+				err = writeBytes(compactNumber, lit)
+			}
+		case '{':
+			err = bw.WriteByte(compactBeginObject)
+		case '}':
+			err = bw.WriteByte(compactEndObject)
+		case '[':
+			err = bw.WriteByte(compactBeginArray)
+		case ']':
+			err = bw.WriteByte(compactEndArray)
+		}
+		if err != nil {
+			return err
+		}
+		if dec.StackDepth() == depth {
+			return bw.Flush()
+		}
+	}
+}
+
+// DecodeCompact reads a value in the compact binary format from r and writes it to enc as JSON.
+// Since r is buffered, DecodeCompact may read past the end of the value.
+func DecodeCompact(r io.Reader, enc *jsontext.Encoder) error {
+	br := bufio.NewReader(r)
+	unexpectedEOF := func(err error) error {
+		if errors.Is(err, io.EOF) {
+			return io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	readBytes := func() (string, error) {
+		n, err := binary.ReadUvarint(br)
+		if err != nil {
+			return "", unexpectedEOF(err)
+		}
+		// grow as data arrives rather than trusting n for allocation.
+		var b strings.Builder
+		copied, err := io.CopyN(&b, br, int64(n))
+		if err != nil {
+			return "", unexpectedEOF(err)
+		}
+		if uint64(copied) != n {
+			return "", io.ErrUnexpectedEOF
+		}
+		return b.String(), nil
+	}
+
+	depth := 0
+	for {
+		tag, err := br.ReadByte()
+		if err != nil {
+			if depth == 0 && errors.Is(err, io.EOF) {
+				return io.EOF
+			}
+			return unexpectedEOF(err)
+		}
+		var tok jsontext.Token
+		switch tag {
+		case compactNull:
+			tok = jsontext.Null
+		case compactFalse:
+			tok = jsontext.False
+		case compactTrue:
+			tok = jsontext.True
+		case compactString:
+			s, err := readBytes()
+			if err != nil {
+				return err
+			}
+			tok = jsontext.String(s)
+		case compactNumber:
+			s, err := readBytes()
+			if err != nil {
+				return err
+			}
+			if err := enc.WriteValue(jsontext.Value(s)); err != nil {
+				return fmt.Errorf("%w: %w", ErrCompactFormat, err)
+			}
+		case compactInt:
+			i, err := binary.ReadVarint(br)
+			if err != nil {
+				return unexpectedEOF(err)
+			}
+			tok = jsontext.Int(i)
+		case compactBeginObject:
+			tok = jsontext.BeginObject
+			depth++
+		case compactEndObject:
+			tok = jsontext.EndObject
+			depth--
+		case compactBeginArray:
+			tok = jsontext.BeginArray
+			depth++
+		case compactEndArray:
+			tok = jsontext.EndArray
+			depth--
+		default:
+			return fmt.Errorf("%w: unknown tag 0x%02x", ErrCompactFormat, tag)
+		}
+		if tag != compactNumber {
+			if err := enc.WriteToken(tok); err != nil {
+				return fmt.Errorf("%w: %w", ErrCompactFormat, err)
+			}
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
+
+func TestCompact(t *testing.T) {
+	const input = `{
+    "null": null,
+    "bools": [true, false],
+    "str": "héllo \"world\"\n",
+    "ints": [0, -1, 1, 9223372036854775807, -9223372036854775808, 123456789012345678901234567890],
+    "floats": [1.5, -0.25, 1e100, 2E-3, 1.0, -0],
+    "nested": {"a": [{}, [], {"b": [[]]}], "": ""}
+}`
+
+	var bin bytes.Buffer
+	err := EncodeCompact(jsontext.NewDecoder(strings.NewReader(input)), &bin)
+	if err != nil {
+		panic(err)
+	}
+	var compacted bytes.Buffer
+	err = jsontext.NewEncoder(&compacted).WriteValue(jsontext.Value(input))
+	if err != nil {
+		panic(err)
+	}
+	t.Logf("json = %d bytes, compact json = %d bytes, compact binary = %d bytes", len(input), compacted.Len()-1, bin.Len())
+
+	var out bytes.Buffer
+	err = DecodeCompact(bytes.NewReader(bin.Bytes()), jsontext.NewEncoder(&out))
+	if err != nil {
+		panic(err)
+	}
+	equal, err := Equal(strings.NewReader(input), bytes.NewReader(out.Bytes()))
+	if err != nil {
+		panic(err)
+	}
+	if !equal {
+		t.Errorf("not equal: expected(%s) != actual(%s)", compacted.String(), out.String())
+	}
+	if !bytes.Equal(bytes.TrimSpace(compacted.Bytes()), bytes.TrimSpace(out.Bytes())) {
+		t.Errorf("literals should be kept as is: expected(%s) != actual(%s)", compacted.String(), out.String())
+	}
+
+	for _, in := range []string{`1`, `"foo"`, `null`, `[]`} {
+		var bin, out bytes.Buffer
+		if err := EncodeCompact(jsontext.NewDecoder(strings.NewReader(in)), &bin); err != nil {
+			panic(err)
+		}
+		if err := DecodeCompact(&bin, jsontext.NewEncoder(&out)); err != nil {
+			panic(err)
+		}
+		if got := strings.TrimSpace(out.String()); got != in {
+			t.Errorf("not equal: expected(%s) != actual(%s)", in, got)
+		}
+	}
+
+	type testCase struct {
+		name     string
+		in       []byte
+		expected error
+	}
+	for _, tc := range []testCase{
+		{"truncated", bin.Bytes()[:bin.Len()/2], io.ErrUnexpectedEOF},
+		{"truncated string", []byte{compactString, 5, 'a'}, io.ErrUnexpectedEOF},
+		{"unknown tag", []byte{0xff}, ErrCompactFormat},
+		{"unbalanced", []byte{compactBeginArray, compactEndObject}, ErrCompactFormat},
+		{"name not string", []byte{compactBeginObject, compactTrue, compactTrue, compactEndObject}, ErrCompactFormat},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := DecodeCompact(bytes.NewReader(tc.in), jsontext.NewEncoder(&out))
+			if !errors.Is(err, tc.expected) {
+				t.Errorf("should be %v, but is %v", tc.expected, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package play
+
+import (
+	"encoding/json/jsontext"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// WithCSVStringFields rewrites a string value at any of pointers, e.g. "a, b,c", into an array of strings
+// split on commas, with surrounding whitespace trimmed from each element.
+// An empty string becomes an empty array. Values of other kinds, including arrays, are passed as is,
+// so the target accepts both forms.
+// A "*" token in pointers matches any token.
+func WithCSVStringFields(pointers ...jsontext.Pointer) DecodeOption {
+	return WithCSVStringFieldsSep(",", pointers...)
+}
+
+// WithCSVStringFieldsSep is like WithCSVStringFields but splits on sep.
+func WithCSVStringFieldsSep(sep string, pointers ...jsontext.Pointer) DecodeOption {
+	return func(c *decodeConfig) {
+		c.hooks = append(c.hooks, func(dec *jsontext.Decoder, tok jsontext.Token, emit func(jsontext.Token) error) error {
+			if tok.Kind() != '"' || justReadName(dec) {
+				return emit(tok)
+			}
+			ptr := dec.StackPointer()
+			if !slices.ContainsFunc(pointers, func(p jsontext.Pointer) bool { return matchPointer(p, ptr) }) {
+				return emit(tok)
+			}
+			s := tok.String()
+			if err := emit(jsontext.BeginArray); err != nil {
+				return err
+			}
+			if s != "" {
+				for elem := range strings.SplitSeq(s, sep) {
+					if err := emit(jsontext.String(strings.TrimSpace(elem))); err != nil {
+						return err
+					}
+				}
+			}
+			return emit(jsontext.EndArray)
+		})
+	}
+}
+
+func TestDecodeOption_CSVStringFields(t *testing.T) {
+	type item struct {
+		Roles []string `json:"roles"`
+	}
+	type sample struct {
+		Tags  []string `json:"tags"`
+		Name  string   `json:"name"`
+		Items []item   `json:"items"`
+	}
+
+	type testCase struct {
+		in       string
+		opt      DecodeOption
+		expected sample
+	}
+	for _, tc := range []testCase{
+		{`{"tags":"x, y,z","name":"a,b"}`, WithCSVStringFields("/tags"), sample{Tags: []string{"x", "y", "z"}, Name: "a,b"}},
+		{`{"tags":["x","y"]}`, WithCSVStringFields("/tags"), sample{Tags: []string{"x", "y"}}},
+		{`{"tags":""}`, WithCSVStringFields("/tags"), sample{Tags: []string{}}},
+		{`{"tags":"a | b"}`, WithCSVStringFieldsSep("|", "/tags"), sample{Tags: []string{"a", "b"}}},
+		{
+			`{"items":[{"roles":"admin,dev"},{"roles":["ops"]}]}`,
+			WithCSVStringFields("/items/*/roles"),
+			sample{Items: []item{{Roles: []string{"admin", "dev"}}, {Roles: []string{"ops"}}}},
+		},
+	} {
+		t.Run(tc.in, func(t *testing.T) {
+			var s sample
+			err := UnmarshalWith([]byte(tc.in), &s, tc.opt)
+			if err != nil {
+				panic(err)
+			}
+			if !slices.Equal(s.Tags, tc.expected.Tags) || (s.Tags == nil) != (tc.expected.Tags == nil) || s.Name != tc.expected.Name {
+				t.Errorf("not equal: expected(%#v) != actual(%#v)", tc.expected, s)
+			}
+			if !slices.EqualFunc(s.Items, tc.expected.Items, func(a, b item) bool { return slices.Equal(a.Roles, b.Roles) }) {
+				t.Errorf("not equal: expected(%#v) != actual(%#v)", tc.expected.Items, s.Items)
+			}
+		})
+	}
+}
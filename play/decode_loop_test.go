@@ -0,0 +1,105 @@
+package play
+
+import (
+	"context"
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+// DecodeLoopContext calls fn for each top-level value read from dec until dec reaches io.EOF.
+// fn must consume exactly one value.
+//
+// ctx is checked before each value, and ctx.Err() is returned once ctx is cancelled.
+// Cancellation does not interrupt fn or a blocking read of the underlying io.Reader.
+func DecodeLoopContext(ctx context.Context, dec *jsontext.Decoder, fn func(*jsontext.Decoder) error) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if dec.PeekKind() == 0 {
+			// EOF or error
+			_, err := dec.ReadToken()
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		// PeekKind may have blocked on reading.
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		off := dec.InputOffset()
+		if err := fn(dec); err != nil {
+			return err
+		}
+		if dec.InputOffset() == off {
+			return fmt.Errorf("decode loop: fn did not consume a value at offset %d", off)
+		}
+	}
+}
+
+func TestDecodeLoopContext(t *testing.T) {
+	type sample struct {
+		Foo int `json:"foo"`
+	}
+
+	dec := jsontext.NewDecoder(strings.NewReader(`{"foo":1} {"foo":2}
+{"foo":3}`))
+	var decoded []sample
+	err := DecodeLoopContext(context.Background(), dec, func(dec *jsontext.Decoder) error {
+		var s sample
+		err := json.UnmarshalDecode(dec, &s)
+		decoded = append(decoded, s)
+		return err
+	})
+	if err != nil {
+		panic(err)
+	}
+	if len(decoded) != 3 || decoded[2].Foo != 3 {
+		t.Errorf("incorrect: %#v", decoded)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	pr, pw := io.Pipe()
+	go func() {
+		for i := range 10 {
+			_, err := fmt.Fprintf(pw, `{"foo":%d}`, i)
+			if err != nil {
+				return
+			}
+		}
+		pw.Close()
+	}()
+
+	decoded = decoded[:0]
+	err = DecodeLoopContext(ctx, jsontext.NewDecoder(pr), func(dec *jsontext.Decoder) error {
+		var s sample
+		err := json.UnmarshalDecode(dec, &s)
+		decoded = append(decoded, s)
+		if s.Foo == 2 {
+			cancel()
+		}
+		return err
+	})
+	pr.Close()
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("should be context.Canceled, but is %v", err)
+	}
+	if len(decoded) != 3 {
+		t.Errorf("should stop after cancellation, but processed %d values", len(decoded))
+	}
+
+	err = DecodeLoopContext(context.Background(), jsontext.NewDecoder(strings.NewReader(`1 2`)), func(dec *jsontext.Decoder) error {
+		return nil
+	})
+	if err == nil {
+		t.Errorf("should cause an error")
+	}
+}
@@ -0,0 +1,227 @@
+package play
+
+import (
+	"bytes"
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// DecodeOption configures UnmarshalWith, UnmarshalReadWith and UnmarshalDecodeWith.
+//
+// json.Options can not be implemented outside of encoding/json,
+// so options are instead applied to the token stream read from the source decoder
+// before the stream reaches the arshaler.
+type DecodeOption func(c *decodeConfig)
+
+type decodeConfig struct {
+	// options for the source decoder; only used when it is created by UnmarshalWith or UnmarshalReadWith.
+	decOpts  []jsontext.Options
+	jsonOpts []json.Options
+	hooks    []decodeHook
+}
+
+// decodeHook is called for each token read from the source decoder.
+// dec is the source decoder which has just read tok.
+// emit passes a token to the next hook, or to the arshaler if the hook is the last one.
+// A hook may call emit zero or more times.
+type decodeHook func(dec *jsontext.Decoder, tok jsontext.Token, emit func(jsontext.Token) error) error
+
+func newDecodeConfig(opts []DecodeOption) *decodeConfig {
+	c := new(decodeConfig)
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// WithJSONOptions passes opts to the underlying unmarshal call.
+func WithJSONOptions(opts ...json.Options) DecodeOption {
+	return func(c *decodeConfig) {
+		c.jsonOpts = append(c.jsonOpts, opts...)
+	}
+}
+
+func UnmarshalWith(data []byte, v any, opts ...DecodeOption) error {
+	return UnmarshalReadWith(bytes.NewReader(data), v, opts...)
+}
+
+func UnmarshalReadWith(r io.Reader, v any, opts ...DecodeOption) error {
+	c := newDecodeConfig(opts)
+	dec := jsontext.NewDecoder(r, c.decOpts...)
+	err := unmarshalDecodeWith(dec, v, c)
+	if err != nil {
+		return err
+	}
+	return checkTrailingData(dec)
+}
+
+// UnmarshalDecodeWith decodes a single value from dec into v.
+// Options only affecting the source decoder are ignored, since dec is already made by the caller.
+func UnmarshalDecodeWith(dec *jsontext.Decoder, v any, opts ...DecodeOption) error {
+	return unmarshalDecodeWith(dec, v, newDecodeConfig(opts))
+}
+
+var errUnmarshalDone = errors.New("unmarshal done")
+
+func unmarshalDecodeWith(dec *jsontext.Decoder, v any, c *decodeConfig) error {
+	if len(c.hooks) == 0 {
+		return json.UnmarshalDecode(dec, v, c.jsonOpts...)
+	}
+
+	// copy options since dec.Options() reflects dec which is read concurrently by streamHooks.
+	opts := json.JoinOptions(dec.Options())
+	pr, pw := io.Pipe()
+
+	var (
+		wg        sync.WaitGroup
+		errStream error
+		panicVal  any
+	)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer func() {
+			if rec := recover(); rec != nil {
+				panicVal = rec
+				errStream = fmt.Errorf("panicked: %v", rec)
+			}
+			pw.CloseWithError(errStream)
+		}()
+		errStream = streamHooks(dec, jsontext.NewEncoder(pw, opts), c.hooks)
+	}()
+
+	err := json.UnmarshalRead(pr, v, append([]json.Options{opts}, c.jsonOpts...)...)
+	// unblock the writer if unmarshaling stopped in middle.
+	pr.CloseWithError(errUnmarshalDone)
+	wg.Wait()
+	if panicVal != nil {
+		panic(panicVal)
+	}
+
+	if errStream != nil && !errors.Is(errStream, errUnmarshalDone) {
+		return errStream
+	}
+	return err
+}
+
+// streamHooks reads exactly one value from dec, passing every token through hooks and then writing it to enc.
+func streamHooks(dec *jsontext.Decoder, enc *jsontext.Encoder, hooks []decodeHook) error {
+	emit := enc.WriteToken
+	for i := len(hooks) - 1; i >= 0; i-- {
+		hook, next := hooks[i], emit
+		emit = func(tok jsontext.Token) error {
+			return hook(dec, tok, next)
+		}
+	}
+
+	depth := dec.StackDepth()
+	for {
+		tok, err := dec.ReadToken()
+		if err != nil {
+			return err
+		}
+		if err := emit(tok); err != nil {
+			return err
+		}
+		if dec.StackDepth() == depth {
+			return nil
+		}
+	}
+}
+
+var ErrInputTooLarge = errors.New("input too large")
+
+// WithMaxInputBytes aborts decoding with ErrInputTooLarge
+// once the source decoder has consumed more than n bytes.
+func WithMaxInputBytes(n int64) DecodeOption {
+	return func(c *decodeConfig) {
+		c.hooks = append(c.hooks, func(dec *jsontext.Decoder, tok jsontext.Token, emit func(jsontext.Token) error) error {
+			if off := dec.InputOffset(); off > n {
+				return fmt.Errorf("%w: consumed %d bytes, limit = %d", ErrInputTooLarge, off, n)
+			}
+			return emit(tok)
+		})
+	}
+}
+
+func TestDecodeOption_MaxInputBytes(t *testing.T) {
+	type sample struct {
+		Foo string
+		Bar []int
+	}
+
+	input := `{"Foo":"foo","Bar":[` + strings.Repeat("1,", 1023) + "1]}"
+
+	var s sample
+	err := UnmarshalWith([]byte(input), &s, WithMaxInputBytes(int64(len(input))))
+	if err != nil {
+		panic(err)
+	}
+	if s.Foo != "foo" || len(s.Bar) != 1024 {
+		t.Errorf("incorrect: %#v", s)
+	}
+
+	var read int64
+	r := &countingReader{r: strings.NewReader(input + strings.Repeat(" ", 64<<10)), n: &read}
+	s = sample{}
+	err = UnmarshalReadWith(r, &s, WithMaxInputBytes(100))
+	if !errors.Is(err, ErrInputTooLarge) {
+		t.Fatalf("should be ErrInputTooLarge, but is %v", err)
+	}
+	t.Logf("err = %v", err)
+	// decode_option_test.go:177: err = input too large: consumed 101 bytes, limit = 100
+	if len(s.Bar) >= 1024 {
+		t.Errorf("should be stopped partway, but decoded %d elements", len(s.Bar))
+	}
+	if read >= int64(len(input)) {
+		t.Errorf("should be stopped partway, but read %d bytes", read)
+	}
+}
+
+type countingReader struct {
+	r io.Reader
+	n *int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	*r.n += int64(n)
+	return n, err
+}
+
+func TestDecodeOption_passthrough(t *testing.T) {
+	type sample struct {
+		Foo Option[string]
+		Bar Either[string, int]
+	}
+	var s sample
+	err := UnmarshalWith(
+		[]byte(`{"Foo":"foo","Bar":123,"Baz":null}`),
+		&s,
+		WithMaxInputBytes(1<<10),
+		WithJSONOptions(json.RejectUnknownMembers(true)),
+	)
+	if err == nil {
+		t.Errorf("should cause an error")
+	}
+	t.Logf("err = %v", err)
+	// decode_option_test.go:213: err = json: cannot unmarshal JSON string into Go play.sample: unknown object member name "Baz"
+
+	err = UnmarshalWith([]byte(`{"Foo":"foo","Bar":123} {}`), &s, WithMaxInputBytes(1<<10))
+	if !errors.Is(err, ErrTrailingData) {
+		t.Errorf("should be ErrTrailingData, but is %v", err)
+	}
+	t.Logf("err = %v", err)
+	// decode_option_test.go:220: err = trailing data: at offset 24
+
+	err = UnmarshalWith([]byte(`{"Foo":"foo","Bar":123} garbage`), &s)
+	if !errors.Is(err, ErrTrailingData) {
+		t.Errorf("should be ErrTrailingData, but is %v", err)
+	}
+}
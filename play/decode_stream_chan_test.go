@@ -0,0 +1,66 @@
+package play
+
+import (
+	"context"
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+// DecodeStreamToChan decodes consecutive top-level values read from r into T and sends each of them on ch,
+// until r reaches io.EOF or decoding fails.
+// ch is not closed; closing it is left to the caller.
+func DecodeStreamToChan[T any](r io.Reader, ch chan<- T) error {
+	return DecodeLoopContext(context.Background(), jsontext.NewDecoder(r), func(dec *jsontext.Decoder) error {
+		var v T
+		if err := json.UnmarshalDecode(dec, &v); err != nil {
+			return err
+		}
+		ch <- v
+		return nil
+	})
+}
+
+func TestDecodeStreamToChan(t *testing.T) {
+	type sample struct {
+		Foo int `json:"foo"`
+	}
+
+	decode := func(input string) ([]sample, error) {
+		ch := make(chan sample)
+		errCh := make(chan error, 1)
+		go func() {
+			defer close(ch)
+			errCh <- DecodeStreamToChan(strings.NewReader(input), ch)
+		}()
+		var received []sample
+		for s := range ch {
+			received = append(received, s)
+		}
+		return received, <-errCh
+	}
+
+	received, err := decode(`{"foo":1}{"foo":2}
+{"foo":3}
+`)
+	if err != nil {
+		panic(err)
+	}
+	if len(received) != 3 || received[0].Foo != 1 || received[1].Foo != 2 || received[2].Foo != 3 {
+		t.Errorf("incorrect: %#v", received)
+	}
+
+	received, err = decode(`{"foo":1} {"foo":"bar"} {"foo":3}`)
+	var semErr *json.SemanticError
+	if !errors.As(err, &semErr) {
+		t.Errorf("should be *json.SemanticError, but is %v", err)
+	}
+	t.Logf("err = %v", err)
+	// decode_stream_chan_test.go:61: err = json: cannot unmarshal JSON string into Go int within "/foo"
+	if len(received) != 1 {
+		t.Errorf("should stop at the error, but received %d values", len(received))
+	}
+}
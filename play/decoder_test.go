@@ -8,8 +8,7 @@ import (
 	"testing"
 )
 
-func TestDecoder(t *testing.T) {
-	const input = `{
+const decoderTestInput = `{
     "foo": null,
     "baz": [
         "qux",
@@ -23,7 +22,9 @@ func TestDecoder(t *testing.T) {
     ]
 }
 `
-	dec := jsontext.NewDecoder(strings.NewReader(input))
+
+func TestDecoder(t *testing.T) {
+	dec := jsontext.NewDecoder(strings.NewReader(decoderTestInput))
 
 	expected := []any{
 		jsontext.BeginObject,
@@ -45,26 +46,26 @@ func TestDecoder(t *testing.T) {
 		idxKind, valueLen := dec.StackIndex(dec.StackDepth())
 		t.Logf("depth = %d, index kind = %s, len at index = %d, stack pointer = %q", dec.StackDepth(), idxKind, valueLen, dec.StackPointer())
 		/*
-		   decoder_test.go:46: depth = 0, index kind = <invalid jsontext.Kind: '\x00'>, len at index = 0, stack pointer = ""
-		   decoder_test.go:46: depth = 1, index kind = {, len at index = 0, stack pointer = ""
-		   decoder_test.go:46: depth = 1, index kind = {, len at index = 1, stack pointer = "/foo"
-		   decoder_test.go:46: depth = 1, index kind = {, len at index = 2, stack pointer = "/foo"
-		   decoder_test.go:46: depth = 1, index kind = {, len at index = 3, stack pointer = "/baz"
-		   decoder_test.go:46: depth = 1, index kind = {, len at index = 3, stack pointer = "/baz"
-		   decoder_test.go:46: depth = 2, index kind = [, len at index = 0, stack pointer = "/baz"
-		   decoder_test.go:46: depth = 2, index kind = [, len at index = 1, stack pointer = "/baz/0"
-		   decoder_test.go:46: depth = 2, index kind = [, len at index = 2, stack pointer = "/baz/1"
-		   decoder_test.go:46: depth = 2, index kind = [, len at index = 2, stack pointer = "/baz/1"
-		   decoder_test.go:46: depth = 2, index kind = [, len at index = 3, stack pointer = "/baz/2"
-		   decoder_test.go:46: depth = 2, index kind = [, len at index = 4, stack pointer = "/baz/3"
-		   decoder_test.go:46: depth = 1, index kind = {, len at index = 4, stack pointer = "/baz"
+		   decoder_test.go:47: depth = 0, index kind = <invalid jsontext.Kind: '\x00'>, len at index = 0, stack pointer = ""
+		   decoder_test.go:47: depth = 1, index kind = {, len at index = 0, stack pointer = ""
+		   decoder_test.go:47: depth = 1, index kind = {, len at index = 1, stack pointer = "/foo"
+		   decoder_test.go:47: depth = 1, index kind = {, len at index = 2, stack pointer = "/foo"
+		   decoder_test.go:47: depth = 1, index kind = {, len at index = 3, stack pointer = "/baz"
+		   decoder_test.go:47: depth = 1, index kind = {, len at index = 3, stack pointer = "/baz"
+		   decoder_test.go:47: depth = 2, index kind = [, len at index = 0, stack pointer = "/baz"
+		   decoder_test.go:47: depth = 2, index kind = [, len at index = 1, stack pointer = "/baz/0"
+		   decoder_test.go:47: depth = 2, index kind = [, len at index = 2, stack pointer = "/baz/1"
+		   decoder_test.go:47: depth = 2, index kind = [, len at index = 2, stack pointer = "/baz/1"
+		   decoder_test.go:47: depth = 2, index kind = [, len at index = 3, stack pointer = "/baz/2"
+		   decoder_test.go:47: depth = 2, index kind = [, len at index = 4, stack pointer = "/baz/3"
+		   decoder_test.go:47: depth = 1, index kind = {, len at index = 4, stack pointer = "/baz"
 		*/
 		switch x := tokenOrValue.(type) {
 		case string:
 			t.Logf("peek = %s", dec.PeekKind())
 		/*
-		   decoder_test.go:62: peek = [
-		   decoder_test.go:62: peek = string
+		   decoder_test.go:65: peek = [
+		   decoder_test.go:65: peek = string
 		*/
 		case jsontext.Token:
 			tok, err := dec.ReadToken()
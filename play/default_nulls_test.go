@@ -0,0 +1,66 @@
+package play
+
+import (
+	"bytes"
+	"encoding/json/jsontext"
+	"strings"
+	"testing"
+)
+
+// DefaultNulls reads a value from dec and writes it to enc
+// replacing null at each pointer in defaults with the corresponding value.
+// Non-null values and nulls at other pointers are written as is.
+func DefaultNulls(dec *jsontext.Decoder, enc *jsontext.Encoder, defaults map[jsontext.Pointer]jsontext.Value) error {
+	depth := dec.StackDepth()
+	for {
+		tok, err := dec.ReadToken()
+		if err != nil {
+			return err
+		}
+		if def, ok := defaults[dec.StackPointer()]; ok && tok.Kind() == 'n' {
+			err = enc.WriteValue(def)
+		} else {
+			err = enc.WriteToken(tok)
+		}
+		if err != nil {
+			return err
+		}
+		if dec.StackDepth() == depth {
+			return nil
+		}
+	}
+}
+
+func TestDefaultNulls(t *testing.T) {
+	defaults := map[jsontext.Pointer]jsontext.Value{
+		"/timeout":       jsontext.Value(`30`),
+		"/retry/backoff": jsontext.Value(`{"base": 1, "max": 60}`),
+		"/hosts/1":       jsontext.Value(`"localhost"`),
+		"/name":          jsontext.Value(`"default"`),
+	}
+
+	type testCase struct {
+		in       string
+		expected string
+	}
+	for _, tc := range []testCase{
+		{`{"timeout":null}`, `{"timeout":30}`},
+		{`{"timeout":10}`, `{"timeout":10}`},
+		{`{"name":"foo","timeout":null,"other":null}`, `{"name":"foo","timeout":30,"other":null}`},
+		{`{"retry":{"backoff":null,"count":null}}`, `{"retry":{"backoff":{"base":1,"max":60},"count":null}}`},
+		{`{"hosts":[null,null,null]}`, `{"hosts":[null,"localhost",null]}`},
+		{`{}`, `{}`},
+		{`null`, `null`},
+	} {
+		t.Run(tc.in, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := DefaultNulls(jsontext.NewDecoder(strings.NewReader(tc.in)), jsontext.NewEncoder(&buf), defaults)
+			if err != nil {
+				panic(err)
+			}
+			if got := strings.TrimSpace(buf.String()); got != tc.expected {
+				t.Errorf("not equal: expected(%s) != actual(%s)", tc.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package play
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"fmt"
+	"testing"
+)
+
+var (
+	_ json.MarshalerTo     = EitherBoth[any, any]{}
+	_ json.UnmarshalerFrom = (*EitherBoth[any, any])(nil)
+)
+
+// EitherBoth holds an L, an R, both or neither.
+// Unlike Either, it is a product rather than a sum: UnmarshalBoth keeps every branch an ambiguous input matches,
+// leaving the resolution to the caller.
+//
+// EitherBoth is encoded as {"left":...,"right":...}; an unset branch is omitted.
+type EitherBoth[L, R any] struct {
+	l Option[L]
+	r Option[R]
+}
+
+func Both[L, R any](l L, r R) EitherBoth[L, R] {
+	return EitherBoth[L, R]{l: Some(l), r: Some(r)}
+}
+
+func (e EitherBoth[L, R]) Left() (L, bool) {
+	return e.l.Value(), e.l.IsSome()
+}
+
+func (e EitherBoth[L, R]) Right() (R, bool) {
+	return e.r.Value(), e.r.IsSome()
+}
+
+func (e EitherBoth[L, R]) BothSet() bool {
+	return e.l.IsSome() && e.r.IsSome()
+}
+
+// UnmarshalBoth decodes val into both L and R, keeping each branch that succeeds.
+// It fails only if neither does.
+func UnmarshalBoth[L, R any](val jsontext.Value, opts ...json.Options) (EitherBoth[L, R], error) {
+	var (
+		e    EitherBoth[L, R]
+		l    L
+		r    R
+		errL = json.Unmarshal(val, &l, opts...)
+		errR = json.Unmarshal(val, &r, opts...)
+	)
+	if errL == nil {
+		e.l = Some(l)
+	}
+	if errR == nil {
+		e.r = Some(r)
+	}
+	if errL != nil && errR != nil {
+		return e, fmt.Errorf("EitherBoth[L, R]: unmarshal failed for both L and R: l = (%w), r = (%w)", errL, errR)
+	}
+	return e, nil
+}
+
+type eitherBothJSON[L, R any] struct {
+	Left  Option[L] `json:"left,omitzero"`
+	Right Option[R] `json:"right,omitzero"`
+}
+
+func (e EitherBoth[L, R]) MarshalJSONTo(enc *jsontext.Encoder) error {
+	return json.MarshalEncode(enc, eitherBothJSON[L, R]{Left: e.l, Right: e.r})
+}
+
+func (e *EitherBoth[L, R]) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	var v eitherBothJSON[L, R]
+	if err := json.UnmarshalDecode(dec, &v); err != nil {
+		return err
+	}
+	e.l, e.r = v.Left, v.Right
+	return nil
+}
+
+func TestEitherBoth(t *testing.T) {
+	type user struct {
+		Name string `json:"name"`
+	}
+	type group struct {
+		Name    string   `json:"name"`
+		Members []string `json:"members"`
+	}
+
+	e, err := UnmarshalBoth[user, group]([]byte(`{"name":"dev"}`))
+	if err != nil {
+		panic(err)
+	}
+	u, okL := e.Left()
+	g, okR := e.Right()
+	if !e.BothSet() || !okL || !okR || u.Name != "dev" || g.Name != "dev" {
+		t.Errorf("both should be kept: %#v", e)
+	}
+
+	bin, err := json.Marshal(e)
+	if err != nil {
+		panic(err)
+	}
+	if expected := `{"left":{"name":"dev"},"right":{"name":"dev","members":[]}}`; string(bin) != expected {
+		t.Errorf("not equal: expected(%s) != actual(%s)", expected, string(bin))
+	}
+	var decoded EitherBoth[user, group]
+	if err := json.Unmarshal(bin, &decoded); err != nil {
+		panic(err)
+	}
+	if u, _ := decoded.Left(); !decoded.BothSet() || u != (user{"dev"}) {
+		t.Errorf("not round-tripped: %#v", decoded)
+	}
+
+	// only one branch matches.
+	e, err = UnmarshalBoth[user, group]([]byte(`{"name":"ops","members":["a"]}`), json.RejectUnknownMembers(true))
+	if err != nil {
+		panic(err)
+	}
+	if _, ok := e.Left(); ok || e.BothSet() {
+		t.Errorf("left should not be set: %#v", e)
+	}
+	bin, err = json.Marshal(e)
+	if err != nil {
+		panic(err)
+	}
+	if expected := `{"right":{"name":"ops","members":["a"]}}`; string(bin) != expected {
+		t.Errorf("not equal: expected(%s) != actual(%s)", expected, string(bin))
+	}
+
+	_, err = UnmarshalBoth[user, group]([]byte(`1`))
+	if err == nil {
+		t.Errorf("should fail for neither")
+	}
+	t.Logf("err = %v", err)
+	// either_both_test.go:135: err = EitherBoth[L, R]: unmarshal failed for both L and R: l = (json: cannot unmarshal JSON number into Go play.user), r = (json: cannot unmarshal JSON number into Go play.group)
+}
@@ -0,0 +1,71 @@
+package play
+
+import (
+	"strconv"
+	"testing"
+)
+
+// FoldEither collapses e into a single value by applying onLeft or onRight, whichever side is set.
+func FoldEither[L, R, T any](e Either[L, R], onLeft func(L) T, onRight func(R) T) T {
+	if e.IsLeft() {
+		return onLeft(e.Left())
+	}
+	return onRight(e.Right())
+}
+
+// Swap flips the sides of e: a left value becomes a right value of the returned Either, and vice versa.
+// Since the zero Either is a zero left, Swap of it is a right holding the zero L.
+func (e Either[L, R]) Swap() Either[R, L] {
+	if e.IsLeft() {
+		return Right[R](e.Left())
+	}
+	return Left[R, L](e.Right())
+}
+
+func TestEitherFold(t *testing.T) {
+	describe := func(e Either[int, string]) string {
+		return FoldEither(e, func(i int) string { return "int " + strconv.Itoa(i) }, func(s string) string { return "string " + s })
+	}
+
+	type testCase struct {
+		in       Either[int, string]
+		expected string
+	}
+	for _, tc := range []testCase{
+		{Left[int, string](5), "int 5"},
+		{Right[int]("foo"), "string foo"},
+		{Either[int, string]{}, "int 0"},
+	} {
+		if got := describe(tc.in); got != tc.expected {
+			t.Errorf("not equal: expected(%s) != actual(%s)", tc.expected, got)
+		}
+	}
+
+	length := FoldEither(Right[int]("foo"), func(i int) int { return i }, func(s string) int { return len(s) })
+	if length != 3 {
+		t.Errorf("not equal: expected(%d) != actual(%d)", 3, length)
+	}
+}
+
+func TestEitherSwap(t *testing.T) {
+	type testCase struct {
+		name     string
+		in       Either[int, string]
+		expected Either[string, int]
+	}
+	for _, tc := range []testCase{
+		{"left", Left[int, string](5), Right[string](5)},
+		{"right", Right[int]("foo"), Left[string, int]("foo")},
+		{"zero", Either[int, string]{}, Right[string](0)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			swapped := tc.in.Swap()
+			if swapped != tc.expected {
+				t.Errorf("not equal: expected(%#v) != actual(%#v)", tc.expected, swapped)
+			}
+			if back := swapped.Swap(); back != tc.in {
+				t.Errorf("not equal: expected(%#v) != actual(%#v)", tc.in, back)
+			}
+		})
+	}
+}
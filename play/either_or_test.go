@@ -0,0 +1,44 @@
+package play
+
+import "testing"
+
+// LeftOr returns the left value of e, or def if e is right.
+// It is the Either counterpart of Option.GetOrElse.
+func (e Either[L, R]) LeftOr(def L) L {
+	if e.IsRight() {
+		return def
+	}
+	return e.Left()
+}
+
+// RightOr returns the right value of e, or def if e is left.
+func (e Either[L, R]) RightOr(def R) R {
+	if e.IsLeft() {
+		return def
+	}
+	return e.Right()
+}
+
+func TestEitherOr(t *testing.T) {
+	type testCase struct {
+		name    string
+		in      Either[int, string]
+		leftOr  int
+		rightOr string
+	}
+	for _, tc := range []testCase{
+		{"left", Left[int, string](5), 5, "default"},
+		{"right", Right[int]("foo"), -1, "foo"},
+		{"zero left", Either[int, string]{}, 0, "default"},
+		{"zero right", Right[int](""), -1, ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.in.LeftOr(-1); got != tc.leftOr {
+				t.Errorf("LeftOr: not equal: expected(%d) != actual(%d)", tc.leftOr, got)
+			}
+			if got := tc.in.RightOr("default"); got != tc.rightOr {
+				t.Errorf("RightOr: not equal: expected(%s) != actual(%s)", tc.rightOr, got)
+			}
+		})
+	}
+}
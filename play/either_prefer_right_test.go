@@ -0,0 +1,82 @@
+package play
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"reflect"
+	"testing"
+)
+
+var (
+	_ json.MarshalerTo     = PreferRight[any, any]{}
+	_ json.UnmarshalerFrom = (*PreferRight[any, any])(nil)
+)
+
+// PreferRight is Either whose unmarshaler tries R before L.
+// It matters only when both may accept the value, e.g. PreferRight[any, string] decodes a string into R.
+// Marshaling is the same as Either.
+type PreferRight[L, R any] struct {
+	Either[L, R]
+}
+
+func (e *PreferRight[L, R]) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	return e.unmarshal(dec, true)
+}
+
+func TestEitherPreferRight(t *testing.T) {
+	type sample struct {
+		Default Either[any, string]      `json:"default"`
+		Right   PreferRight[any, string] `json:"right"`
+	}
+
+	type testCase struct {
+		in       string
+		expected sample
+	}
+	for _, tc := range []testCase{
+		{
+			// both sides accept a string.
+			`{"default":"foo","right":"foo"}`,
+			sample{Left[any, string]("foo"), PreferRight[any, string]{Right[any]("foo")}},
+		},
+		{
+			// only L accepts a number; falls back to L.
+			`{"default":1,"right":1}`,
+			sample{Left[any, string](1.0), PreferRight[any, string]{Left[any, string](1.0)}},
+		},
+	} {
+		t.Run(tc.in, func(t *testing.T) {
+			var s sample
+			if err := json.Unmarshal([]byte(tc.in), &s); err != nil {
+				panic(err)
+			}
+			if !reflect.DeepEqual(s, tc.expected) {
+				t.Errorf("not equal: expected(%#v) != actual(%#v)", tc.expected, s)
+			}
+			bin, err := json.Marshal(s)
+			if err != nil {
+				panic(err)
+			}
+			if string(bin) != tc.in {
+				t.Errorf("not equal: expected(%s) != actual(%s)", tc.in, string(bin))
+			}
+		})
+	}
+
+	// the fast path for disjoint kinds is not affected by the order.
+	var e PreferRight[int, string]
+	if err := json.Unmarshal([]byte(`1`), &e); err != nil {
+		panic(err)
+	}
+	if !e.IsLeft() || e.Left() != 1 {
+		t.Errorf("incorrect: %#v", e)
+	}
+
+	var failing PreferRight[int, bool]
+	err := json.Unmarshal([]byte(`"foo"`), &failing)
+	if err == nil {
+		t.Errorf("should cause an error")
+	}
+	t.Logf("err = %v", err)
+	// either_prefer_right_test.go:80: err = json: cannot unmarshal into Go play.PreferRight[int,bool]: Either[L, R]: unmarshal failed for both L and R: l = (json: cannot unmarshal JSON string into Go int), r = (json: cannot unmarshal JSON string into Go bool)
+}
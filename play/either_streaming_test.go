@@ -0,0 +1,79 @@
+package play
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"testing"
+)
+
+var _ json.MarshalerTo = (*streamingArray)(nil)
+
+// streamingArray writes n numbers without holding them in memory.
+type streamingArray struct {
+	n int
+	// called after each element is written
+	onElem func(i int)
+}
+
+func (a *streamingArray) MarshalJSONTo(enc *jsontext.Encoder) error {
+	if err := enc.WriteToken(jsontext.BeginArray); err != nil {
+		return err
+	}
+	for i := range a.n {
+		if err := enc.WriteToken(jsontext.Int(int64(i))); err != nil {
+			return err
+		}
+		if a.onElem != nil {
+			a.onElem(i)
+		}
+	}
+	return enc.WriteToken(jsontext.EndArray)
+}
+
+type countingWriter struct {
+	n        int64
+	writes   int
+	maxWrite int
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	w.writes++
+	w.maxWrite = max(w.maxWrite, len(p))
+	return len(p), nil
+}
+
+func TestEitherStreaming(t *testing.T) {
+	const n = 1 << 20
+
+	type sample struct {
+		Foo Either[int, streamingArray]
+	}
+
+	w := new(countingWriter)
+	var writtenAtHalf int64 = -1
+	s := sample{
+		Foo: Right[int](streamingArray{
+			n: n,
+			onElem: func(i int) {
+				if i == n/2 {
+					writtenAtHalf = w.n
+				}
+			},
+		}),
+	}
+
+	err := json.MarshalWrite(w, s)
+	if err != nil {
+		panic(err)
+	}
+	t.Logf("total = %d, writes = %d, max write = %d, written at half = %d", w.n, w.writes, w.maxWrite, writtenAtHalf)
+	// either_streaming_test.go:70: total = 7277507, writes = 2380, max write = 3080, written at half = 3557422
+
+	if writtenAtHalf <= 0 {
+		t.Errorf("nothing is written to the writer before marshaling completes")
+	}
+	if w.maxWrite > 64<<10 {
+		t.Errorf("output is buffered: max write = %d", w.maxWrite)
+	}
+}
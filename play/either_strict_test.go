@@ -0,0 +1,86 @@
+package play
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+var ErrAmbiguousEither = errors.New("ambiguous Either")
+
+// EitherStrictUnambiguous returns options that unmarshal Either[L, R] by trying both L and R,
+// failing with ErrAmbiguousEither if both succeed instead of silently preferring L.
+//
+// This is meant to surface union-design bugs, e.g. both branches being structs which accept any object.
+func EitherStrictUnambiguous[L, R any]() json.Options {
+	return json.WithUnmarshalers(json.UnmarshalFromFunc(func(dec *jsontext.Decoder, e *Either[L, R]) error {
+		val, err := dec.ReadValue()
+		if err != nil {
+			return err
+		}
+
+		var l L
+		errL := json.Unmarshal(val, &l, dec.Options())
+		var r R
+		errR := json.Unmarshal(val, &r, dec.Options())
+
+		switch {
+		case errL == nil && errR == nil:
+			return fmt.Errorf("%w: both L and R accept %s", ErrAmbiguousEither, val)
+		case errL == nil:
+			*e = Left[L, R](l)
+			return nil
+		case errR == nil:
+			*e = Right[L](r)
+			return nil
+		default:
+			return fmt.Errorf("Either[L, R]: unmarshal failed for both L and R: l = (%w), r = (%w)", errL, errR)
+		}
+	}))
+}
+
+func TestEitherStrict(t *testing.T) {
+	type empty1 struct{}
+	type empty2 struct{}
+
+	var e Either[empty1, empty2]
+	err := json.Unmarshal([]byte(`{}`), &e)
+	if err != nil {
+		panic(err)
+	}
+	if !e.IsLeft() {
+		t.Errorf("should be left without strict mode")
+	}
+
+	err = json.Unmarshal([]byte(`{}`), &e, EitherStrictUnambiguous[empty1, empty2]())
+	if !errors.Is(err, ErrAmbiguousEither) {
+		t.Errorf("should be ErrAmbiguousEither, but is %v", err)
+	}
+	t.Logf("err = %v", err)
+	// either_strict_test.go:61: err = json: cannot unmarshal into Go ptr: ambiguous Either: both L and R accept {}
+
+	type testCase struct {
+		in      string
+		isRight bool
+		fail    bool
+	}
+	for _, tc := range []testCase{
+		{`"foo"`, false, false},
+		{`123`, true, false},
+		{`false`, false, true},
+	} {
+		var e Either[string, int]
+		err := json.Unmarshal([]byte(tc.in), &e, EitherStrictUnambiguous[string, int]())
+		if (err != nil) != tc.fail {
+			t.Errorf("incorrect: in = %s, err = %v", tc.in, err)
+		}
+		if err == nil && e.IsRight() != tc.isRight {
+			t.Errorf("incorrect: in = %s, isRight = %t", tc.in, e.IsRight())
+		}
+		if errors.Is(err, ErrAmbiguousEither) {
+			t.Errorf("should not be ambiguous: in = %s", tc.in)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package play
+
+import (
+	"encoding/json/v2"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+var ErrEitherStructSides = errors.New("exactly one of Left and Right must be set")
+
+// EitherStruct is a plain-struct view of Either, as used by systems modeling unions as structs of optional fields.
+// Exactly one of Left and Right is non-nil.
+type EitherStruct[L, R any] struct {
+	Left  *L `json:"left,omitzero"`
+	Right *R `json:"right,omitzero"`
+}
+
+// EitherToStruct returns e as EitherStruct pointing to a copy of the value of the set side.
+func EitherToStruct[L, R any](e Either[L, R]) EitherStruct[L, R] {
+	if e.IsLeft() {
+		l := e.Left()
+		return EitherStruct[L, R]{Left: &l}
+	}
+	r := e.Right()
+	return EitherStruct[L, R]{Right: &r}
+}
+
+// EitherFromStruct is the inverse of EitherToStruct.
+// It returns an error wrapping ErrEitherStructSides unless exactly one side of s is set.
+func EitherFromStruct[L, R any](s EitherStruct[L, R]) (Either[L, R], error) {
+	switch {
+	case s.Left != nil && s.Right == nil:
+		return Left[L, R](*s.Left), nil
+	case s.Left == nil && s.Right != nil:
+		return Right[L](*s.Right), nil
+	case s.Left != nil:
+		return Either[L, R]{}, fmt.Errorf("%w: both are set", ErrEitherStructSides)
+	default:
+		return Either[L, R]{}, fmt.Errorf("%w: neither is set", ErrEitherStructSides)
+	}
+}
+
+func TestEitherStruct(t *testing.T) {
+	type testCase struct {
+		name     string
+		in       Either[string, int]
+		expected string
+	}
+	for _, tc := range []testCase{
+		{"left", Left[string, int]("foo"), `{"left":"foo"}`},
+		{"right", Right[string](5), `{"right":5}`},
+		{"zero left", Left[string, int](""), `{"left":""}`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s := EitherToStruct(tc.in)
+			bin, err := json.Marshal(s)
+			if err != nil {
+				panic(err)
+			}
+			if string(bin) != tc.expected {
+				t.Errorf("not equal: expected(%s) != actual(%s)", tc.expected, string(bin))
+			}
+			var decoded EitherStruct[string, int]
+			if err := json.Unmarshal(bin, &decoded); err != nil {
+				panic(err)
+			}
+			e, err := EitherFromStruct(decoded)
+			if err != nil {
+				panic(err)
+			}
+			if e != tc.in {
+				t.Errorf("not equal: expected(%#v) != actual(%#v)", tc.in, e)
+			}
+		})
+	}
+
+	l, r := "foo", 5
+	for _, s := range []EitherStruct[string, int]{{Left: &l, Right: &r}, {}} {
+		_, err := EitherFromStruct(s)
+		if !errors.Is(err, ErrEitherStructSides) {
+			t.Errorf("should be ErrEitherStructSides, but is %v", err)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package play
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+var ErrUnknownEitherTag = errors.New("unknown Either tag")
+
+// EitherTaggedArray returns options that marshal Either[L, R] as a positional array of [tag, value],
+// where tag is leftTag or rightTag, e.g. ["left", "foo"] or ["right", 5],
+// and unmarshal it dispatching on the tag instead of trying both branches.
+//
+// An unknown tag is an error wrapping ErrUnknownEitherTag,
+// and an array not having exactly 2 elements is an error wrapping ErrArity.
+func EitherTaggedArray[L, R any](leftTag, rightTag string) json.Options {
+	return json.JoinOptions(
+		json.WithMarshalers(json.MarshalToFunc(func(enc *jsontext.Encoder, e Either[L, R]) error {
+			if err := enc.WriteToken(jsontext.BeginArray); err != nil {
+				return err
+			}
+			var err error
+			if e.IsLeft() {
+				if err = enc.WriteToken(jsontext.String(leftTag)); err == nil {
+					err = json.MarshalEncode(enc, e.Left())
+				}
+			} else {
+				if err = enc.WriteToken(jsontext.String(rightTag)); err == nil {
+					err = json.MarshalEncode(enc, e.Right())
+				}
+			}
+			if err != nil {
+				return err
+			}
+			return enc.WriteToken(jsontext.EndArray)
+		})),
+		json.WithUnmarshalers(json.UnmarshalFromFunc(func(dec *jsontext.Decoder, e *Either[L, R]) error {
+			if err := readBegin(dec, '['); err != nil {
+				return err
+			}
+			if dec.PeekKind() == ']' {
+				return fmt.Errorf("%w: tagged Either must be [tag, value], but is empty", ErrArity)
+			}
+			tok, err := dec.ReadToken()
+			if err != nil {
+				return err
+			}
+			if tok.Kind() != '"' {
+				return fmt.Errorf("%w: tag must be a string, but is %s", ErrUnknownEitherTag, tok.Kind())
+			}
+			tag := tok.String()
+			if dec.PeekKind() == ']' {
+				return fmt.Errorf("%w: tagged Either must be [tag, value], but value is missing", ErrArity)
+			}
+
+			var decoded Either[L, R]
+			switch tag {
+			case leftTag:
+				var l L
+				if err := json.UnmarshalDecode(dec, &l); err != nil {
+					return err
+				}
+				decoded = Left[L, R](l)
+			case rightTag:
+				var r R
+				if err := json.UnmarshalDecode(dec, &r); err != nil {
+					return err
+				}
+				decoded = Right[L](r)
+			default:
+				return fmt.Errorf("%w: %q, expected %q or %q", ErrUnknownEitherTag, tag, leftTag, rightTag)
+			}
+
+			if dec.PeekKind() != ']' {
+				return fmt.Errorf("%w: tagged Either must be [tag, value], but has more elements", ErrArity)
+			}
+			if _, err := dec.ReadToken(); err != nil {
+				return err
+			}
+			*e = decoded
+			return nil
+		})),
+	)
+}
+
+func TestEitherTaggedArray(t *testing.T) {
+	opt := EitherTaggedArray[string, int]("left", "right")
+
+	var e Either[string, int]
+	err := json.Unmarshal([]byte(`["right", 5]`), &e, opt)
+	if err != nil {
+		panic(err)
+	}
+	if !e.IsRight() || e.Right() != 5 {
+		t.Errorf("incorrect: %#v", e)
+	}
+	bin, err := json.Marshal(e, opt)
+	if err != nil {
+		panic(err)
+	}
+	if string(bin) != `["right",5]` {
+		t.Errorf("not equal: expected(%s) != actual(%s)", `["right",5]`, string(bin))
+	}
+
+	// tags are configurable and the value is not tried against the other branch.
+	type sample struct {
+		V []Either[string, string] `json:"v"`
+	}
+	kvOpt := EitherTaggedArray[string, string]("k", "v")
+	var s sample
+	err = json.Unmarshal([]byte(`{"v":[["k","foo"],["v","bar"]]}`), &s, kvOpt)
+	if err != nil {
+		panic(err)
+	}
+	if len(s.V) != 2 || !s.V[0].IsLeft() || s.V[0].Left() != "foo" || !s.V[1].IsRight() || s.V[1].Right() != "bar" {
+		t.Errorf("incorrect: %#v", s)
+	}
+	bin, err = json.Marshal(s, kvOpt)
+	if err != nil {
+		panic(err)
+	}
+	if expected := `{"v":[["k","foo"],["v","bar"]]}`; string(bin) != expected {
+		t.Errorf("not equal: expected(%s) != actual(%s)", expected, string(bin))
+	}
+
+	type testCase struct {
+		in       string
+		expected error
+	}
+	for _, tc := range []testCase{
+		{`["middle", 5]`, ErrUnknownEitherTag},
+		{`[1, 5]`, ErrUnknownEitherTag},
+		{`[]`, ErrArity},
+		{`["left"]`, ErrArity},
+		{`["left", "foo", 1]`, ErrArity},
+	} {
+		t.Run(tc.in, func(t *testing.T) {
+			var e Either[string, int]
+			err := json.Unmarshal([]byte(tc.in), &e, opt)
+			if !errors.Is(err, tc.expected) {
+				t.Errorf("should be %v, but is %v", tc.expected, err)
+			}
+		})
+	}
+
+	err = json.Unmarshal([]byte(`["left", 5]`), &e, opt)
+	if err == nil {
+		t.Errorf("should cause an error")
+	}
+	t.Logf("err = %v", err)
+	// either_tagged_array_test.go:153: err = json: cannot unmarshal JSON number into Go string within "/1"
+}
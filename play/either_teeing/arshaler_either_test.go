@@ -2,6 +2,7 @@ package play
 
 import (
 	"bytes"
+	"context"
 	"encoding/json/jsontext"
 	"encoding/json/v2"
 	"errors"
@@ -10,6 +11,7 @@ import (
 	"strings"
 	"sync"
 	"testing"
+	"time"
 )
 
 var (
@@ -129,12 +131,14 @@ type teeReader struct {
 
 func (r *teeReader) Read(p []byte) (int, error) {
 	r.mu.Lock()
-	defer r.mu.Unlock()
-
 	if r.closed {
+		r.mu.Unlock()
 		return 0, io.EOF
 	}
+	r.mu.Unlock()
 
+	// mu is not held while blocked, so that Stop and Close can interrupt the read;
+	// closing the pipe unblocks a pending Read.
 	return r.r.Read(p)
 }
 
@@ -287,7 +291,140 @@ func TeeDecoder(dec *jsontext.Decoder, encOptions ...jsontext.Options) (l ReadCl
 	}
 }
 
+var ErrBranchTimeout = errors.New("branch timeout")
+
+// eitherUnmarshaler is implemented by *Either[L, R] for any L and R,
+// so that options can be applied to every instantiation at once.
+type eitherUnmarshaler interface {
+	unmarshalJSONFrom(dec *jsontext.Decoder, newDeadline deadlineFunc) error
+}
+
+// deadlineFunc returns a context canceled, with the cause, once a branch of side "L" or "R" is to be abandoned.
+// The returned cancel func is called when the branch starts waiting for input or finishes.
+type deadlineFunc func(side string) (context.Context, context.CancelFunc)
+
+// WithEitherBranchTimeout returns options that abandon a branch of Either decoding an object or an array
+// once it spends d without reading its input, failing that branch with ErrBranchTimeout so that the other branch may still succeed.
+//
+// A branch that does not read its input blocks the other branch from reading as well,
+// since both are fed from a single decoder.
+// Thus time spent waiting for input is not counted: the deadline is armed only while the branch is out of Read,
+// and is re-armed every time a Read returns.
+// The input of an abandoned branch is closed, so its goroutine exits once its unmarshaler reads again or returns;
+// its result (including a panic) is discarded.
+func WithEitherBranchTimeout(d time.Duration) json.Options {
+	return json.WithUnmarshalers(eitherBranchUnmarshalers(branchTimeout(d)))
+}
+
+func branchTimeout(d time.Duration) deadlineFunc {
+	return func(side string) (context.Context, context.CancelFunc) {
+		return context.WithTimeoutCause(
+			context.Background(),
+			d,
+			fmt.Errorf("%w: %s did not read its input in %s", ErrBranchTimeout, side, d),
+		)
+	}
+}
+
+func eitherBranchUnmarshalers(newDeadline deadlineFunc) *json.Unmarshalers {
+	return json.UnmarshalFromFunc(func(dec *jsontext.Decoder, e eitherUnmarshaler) error {
+		return e.unmarshalJSONFrom(dec, newDeadline)
+	})
+}
+
 func (e *Either[L, R]) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	return e.unmarshalJSONFrom(dec, nil)
+}
+
+type branchResult struct {
+	err      error
+	panicked bool
+	panicVal any
+}
+
+// branch is a side of Either reading through ReadCloseStopper.
+// If newDeadline is non-nil, a deadline is armed while the branch is not waiting in Read,
+// and the branch is abandoned once it expires.
+type branch struct {
+	ReadCloseStopper
+	side        string
+	newDeadline deadlineFunc
+	done        chan branchResult
+
+	mu       sync.Mutex
+	finished bool
+	disarm   func()
+}
+
+func newBranch(rd ReadCloseStopper, side string, newDeadline deadlineFunc) *branch {
+	// buffered so that an abandoned branch can exit without a receiver.
+	return &branch{ReadCloseStopper: rd, side: side, newDeadline: newDeadline, done: make(chan branchResult, 1)}
+}
+
+func (b *branch) Read(p []byte) (int, error) {
+	b.setArmed(false)
+	defer b.setArmed(true)
+	return b.ReadCloseStopper.Read(p)
+}
+
+func (b *branch) setArmed(armed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.disarm != nil {
+		b.disarm()
+		b.disarm = nil
+	}
+	if !armed || b.finished || b.newDeadline == nil {
+		return
+	}
+	ctx, cancel := b.newDeadline(b.side)
+	stop := context.AfterFunc(ctx, func() {
+		b.ReadCloseStopper.Stop(false)
+		b.finish(branchResult{err: context.Cause(ctx)})
+	})
+	b.disarm = func() {
+		stop()
+		cancel()
+	}
+}
+
+// finish reports res unless the branch is already finished, by returning or by being abandoned.
+func (b *branch) finish(res branchResult) {
+	b.mu.Lock()
+	if b.finished {
+		b.mu.Unlock()
+		return
+	}
+	b.finished = true
+	if b.disarm != nil {
+		b.disarm()
+		b.disarm = nil
+	}
+	b.mu.Unlock()
+	b.done <- res
+}
+
+// run unmarshals the branch into v in a new goroutine.
+func (b *branch) run(v any, opts json.Options, stopOnSuccess bool) {
+	b.setArmed(true)
+	go func() {
+		var res branchResult
+		defer func() {
+			if rec := recover(); rec != nil {
+				res.panicked = true
+				res.panicVal = rec
+			}
+			// when left succeeds, stop right as well since left is preferred.
+			b.Stop(stopOnSuccess && res.err == nil && !res.panicked)
+			b.finish(res)
+		}()
+		res.err = json.UnmarshalRead(b, v, opts)
+	}()
+}
+
+// unmarshalJSONFrom unmarshals dec into e. If newDeadline is non-nil,
+// a branch decoding an object or an array is abandoned once its deadline expires.
+func (e *Either[L, R]) unmarshalJSONFrom(dec *jsontext.Decoder, newDeadline deadlineFunc) error {
 	eitherErr := func(errL, errR error) error {
 		return fmt.Errorf("Either[L, R]: unmarshal failed for both L and R: l = (%w), r = (%w)", errL, errR)
 	}
@@ -318,10 +455,8 @@ func (e *Either[L, R]) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
 
 		return eitherErr(errL, errR)
 	case '{', '[': // maybe deep and large
-		var wg sync.WaitGroup
-		defer wg.Wait() // in case of panic
-
-		var err error
+		// copy options since dec.Options() reflects dec which is read concurrently by TeeDecoder.
+		opts := json.JoinOptions(dec.Options())
 
 		rl, rr, wait, err := TeeDecoder(dec)
 		if err != nil {
@@ -334,47 +469,35 @@ func (e *Either[L, R]) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
 		}()
 
 		var (
-			l          L
-			r          R
-			errL, errR error
-			panicVal   any
+			l L
+			r R
 		)
-
-		wg.Add(1)
-		go func() {
-			defer func() {
-				if rec := recover(); rec != nil {
-					panicVal = rec
-				}
-				rr.Stop(false)
-				wg.Done()
-			}()
-			errR = json.UnmarshalRead(rr, &r, dec.Options())
-		}()
-
-		errL = json.UnmarshalRead(rl, &l, dec.Options())
-		rl.Stop(errL == nil)
-
-		wg.Wait()
-		if panicVal != nil {
-			panic(panicVal)
+		bl, br := newBranch(rl, "L", newDeadline), newBranch(rr, "R", newDeadline)
+		bl.run(&l, opts, true)
+		br.run(&r, opts, false)
+		resL, resR := <-bl.done, <-br.done
+		if resL.panicked {
+			panic(resL.panicVal)
+		}
+		if resR.panicked {
+			panic(resR.panicVal)
 		}
 
-		if errL == nil {
+		if resL.err == nil {
 			e.isRight = false
 			e.l = l
 			e.r = *new(R)
 			return nil
 		}
 
-		if errR == nil {
+		if resR.err == nil {
 			e.isRight = true
 			e.l = *new(L)
 			e.r = r
 			return nil
 		}
 
-		return eitherErr(errL, errR)
+		return eitherErr(resL.err, resR.err)
 	default: // invalid, '}',	']'
 		// syntax error
 		_, err := dec.ReadValue()
@@ -403,13 +526,13 @@ func TestArshalerEither(t *testing.T) {
 			/*
 			   === RUN   TestArshalerEither
 			   === RUN   TestArshalerEither/"foo"
-			       arshaler_either_test.go:402: err = <nil>
+			       arshaler_either_test.go:489: err = <nil>
 			   === RUN   TestArshalerEither/123
-			       arshaler_either_test.go:402: err = <nil>
+			       arshaler_either_test.go:489: err = <nil>
 			   === RUN   TestArshalerEither/false
-			       arshaler_either_test.go:402: err = json: cannot unmarshal into Go play.Either[string,int]: Either[L, R]: unmarshal failed for both L and R: l = (json: cannot unmarshal JSON boolean into Go string), r = (json: cannot unmarshal JSON boolean into Go int)
+			       arshaler_either_test.go:489: err = json: cannot unmarshal into Go play.Either[string,int]: Either[L, R]: unmarshal failed for both L and R: l = (json: cannot unmarshal JSON boolean into Go string), r = (json: cannot unmarshal JSON boolean into Go int)
 			   === RUN   TestArshalerEither/{"foo":_false}
-			       arshaler_either_test.go:402: err = json: cannot unmarshal into Go play.Either[string,int] after offset 13: Either[L, R]: unmarshal failed for both L and R: l = (json: cannot unmarshal JSON object into Go string), r = (json: cannot unmarshal JSON object into Go int)
+			       arshaler_either_test.go:489: err = json: cannot unmarshal into Go play.Either[string,int] after offset 13: Either[L, R]: unmarshal failed for both L and R: l = (json: cannot unmarshal JSON object into Go string), r = (json: cannot unmarshal JSON object into Go int)
 			*/
 		})
 	}
@@ -440,23 +563,23 @@ func TestArshalerEither(t *testing.T) {
 			t.Logf("err = %v", err)
 			/*
 			   === RUN   TestArshalerEither/"foo"#01
-			       arshaler_either_test.go:440: err = json: cannot unmarshal into Go struct: Either[L, R]: unmarshal failed for both L and R: l = (json: cannot unmarshal JSON string into Go play.sampleL), r = (json: cannot unmarshal JSON string into Go play.sampleR)
+			       arshaler_either_test.go:527: err = json: cannot unmarshal into Go struct: Either[L, R]: unmarshal failed for both L and R: l = (json: cannot unmarshal JSON string into Go play.sampleL), r = (json: cannot unmarshal JSON string into Go play.sampleR)
 			   === RUN   TestArshalerEither/123#01
-			       arshaler_either_test.go:440: err = json: cannot unmarshal into Go struct: Either[L, R]: unmarshal failed for both L and R: l = (json: cannot unmarshal JSON number into Go play.sampleL), r = (json: cannot unmarshal JSON number into Go play.sampleR)
+			       arshaler_either_test.go:527: err = json: cannot unmarshal into Go struct: Either[L, R]: unmarshal failed for both L and R: l = (json: cannot unmarshal JSON number into Go play.sampleL), r = (json: cannot unmarshal JSON number into Go play.sampleR)
 			   === RUN   TestArshalerEither/false#01
-			       arshaler_either_test.go:440: err = json: cannot unmarshal into Go struct: Either[L, R]: unmarshal failed for both L and R: l = (json: cannot unmarshal JSON boolean into Go play.sampleL), r = (json: cannot unmarshal JSON boolean into Go play.sampleR)
+			       arshaler_either_test.go:527: err = json: cannot unmarshal into Go struct: Either[L, R]: unmarshal failed for both L and R: l = (json: cannot unmarshal JSON boolean into Go play.sampleL), r = (json: cannot unmarshal JSON boolean into Go play.sampleR)
 			   === RUN   TestArshalerEither/{"foo":_false}#01
-			       arshaler_either_test.go:440: err = json: cannot unmarshal into Go struct after offset 13: Either[L, R]: unmarshal failed for both L and R: l = (json: cannot unmarshal JSON string into Go play.sampleL: unknown object member name "foo"), r = (json: cannot unmarshal JSON string into Go play.sampleR: unknown object member name "foo")
+			       arshaler_either_test.go:527: err = json: cannot unmarshal into Go struct after offset 13: Either[L, R]: unmarshal failed for both L and R: l = (json: cannot unmarshal JSON string into Go play.sampleL: unknown object member name "foo"), r = (json: cannot unmarshal JSON string into Go play.sampleR: unknown object member name "foo")
 			   === RUN   TestArshalerEither/{"Foo":_false}
-			       arshaler_either_test.go:440: err = json: cannot unmarshal into Go struct after offset 13: Either[L, R]: unmarshal failed for both L and R: l = (json: cannot unmarshal JSON boolean into Go []int within "/Foo"), r = (json: cannot unmarshal JSON string into Go play.sampleR: unknown object member name "Foo")
+			       arshaler_either_test.go:527: err = json: cannot unmarshal into Go struct after offset 13: Either[L, R]: unmarshal failed for both L and R: l = (json: cannot unmarshal JSON boolean into Go []int within "/Foo"), r = (json: cannot unmarshal JSON string into Go play.sampleR: unknown object member name "Foo")
 			   === RUN   TestArshalerEither/{"Foo":_[1,2,3]}
-			       arshaler_either_test.go:440: err = <nil>
+			       arshaler_either_test.go:527: err = <nil>
 			   === RUN   TestArshalerEither/{"Bar":_{"foo":"foofoo","bar":"barbar"}}
-			       arshaler_either_test.go:440: err = <nil>
+			       arshaler_either_test.go:527: err = <nil>
 			   === RUN   TestArshalerEither/{"Foo":_[1,2,3}
-			       arshaler_either_test.go:440: err = json: cannot unmarshal into Go struct within "/Foo": Either[L, R]: unmarshal failed for both L and R: l = (jsontext: read error: jsontext: invalid character '}' after array element (expecting ',' or ']') within "/Foo" after offset 14), r = (json: cannot unmarshal JSON string into Go play.sampleR: unknown object member name "Foo")
+			       arshaler_either_test.go:527: err = json: cannot unmarshal into Go struct within "/Foo": Either[L, R]: unmarshal failed for both L and R: l = (jsontext: read error: jsontext: invalid character '}' after array element (expecting ',' or ']') within "/Foo" after offset 14), r = (json: cannot unmarshal JSON string into Go play.sampleR: unknown object member name "Foo")
 			   === RUN   TestArshalerEither/{"Bar":_{"foo":}}
-			       arshaler_either_test.go:440: err = json: cannot unmarshal into Go struct within "/Bar/foo": Either[L, R]: unmarshal failed for both L and R: l = (jsontext: read error: jsontext: missing value after object name within "/Bar/foo" after offset 15), r = (jsontext: read error: jsontext: missing value after object name within "/Bar/foo" after offset 15)
+			       arshaler_either_test.go:527: err = json: cannot unmarshal into Go struct within "/Bar/foo": Either[L, R]: unmarshal failed for both L and R: l = (jsontext: read error: jsontext: missing value after object name within "/Bar/foo" after offset 15), r = (jsontext: read error: jsontext: missing value after object name within "/Bar/foo" after offset 15)
 			*/
 		})
 	}
@@ -517,3 +640,179 @@ func TestArshalerEither_panic(t *testing.T) {
 		json.Unmarshal([]byte(`{"foo":"foo","bar":"bar"}`), &e)
 	})
 }
+
+// fakeDeadlines hands deadlines out to branches and expires them only on demand.
+type fakeDeadlines struct {
+	mu     sync.Mutex
+	latest map[string]context.CancelCauseFunc
+}
+
+func (f *fakeDeadlines) newDeadline(side string) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.latest == nil {
+		f.latest = make(map[string]context.CancelCauseFunc)
+	}
+	f.latest[side] = cancel
+	return ctx, func() { cancel(nil) }
+}
+
+// expire expires the deadline last armed for side.
+func (f *fakeDeadlines) expire(side string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.latest[side](fmt.Errorf("%w: %s expired", ErrBranchTimeout, side))
+}
+
+// stallDecoder stalls before reading its input.
+type stallDecoder struct{}
+
+// stallAfterReadDecoder stalls after reading its input.
+type stallAfterReadDecoder struct{}
+
+// stalls controls stallDecoder and stallAfterReadDecoder.
+type stalls struct {
+	stalled chan string // receives the decoder name once it stalls.
+	release chan struct{}
+	readErr chan error // receives the error of the read stallDecoder does after released.
+}
+
+func newStalls() *stalls {
+	return &stalls{make(chan string, 2), make(chan struct{}), make(chan error, 2)}
+}
+
+func (s *stalls) unmarshalers() *json.Unmarshalers {
+	return json.JoinUnmarshalers(
+		json.UnmarshalFromFunc(func(dec *jsontext.Decoder, _ *stallDecoder) error {
+			s.stalled <- "stallDecoder"
+			<-s.release
+			err := dec.SkipValue()
+			s.readErr <- err
+			return err
+		}),
+		json.UnmarshalFromFunc(func(dec *jsontext.Decoder, _ *stallAfterReadDecoder) error {
+			if err := dec.SkipValue(); err != nil {
+				return err
+			}
+			s.stalled <- "stallAfterReadDecoder"
+			<-s.release
+			return nil
+		}),
+	)
+}
+
+// unmarshalAsync unmarshals in to v in a new goroutine, sending the result to the returned channel.
+func unmarshalAsync(in string, v any, opts ...json.Options) <-chan error {
+	ch := make(chan error, 1)
+	go func() {
+		ch <- json.Unmarshal([]byte(in), v, opts...)
+	}()
+	return ch
+}
+
+func TestArshalerEither_timeout(t *testing.T) {
+	// large enough that the branches can not read it without the tee writing to both.
+	input := `{"foo":"foo","bar":[` + strings.Repeat(`"baz",`, 16<<10) + `"baz"]}`
+
+	t.Run("stalled left", func(t *testing.T) {
+		var (
+			f fakeDeadlines
+			s = newStalls()
+			e Either[stallDecoder, map[string]any]
+		)
+		result := unmarshalAsync(input, &e, json.WithUnmarshalers(json.JoinUnmarshalers(eitherBranchUnmarshalers(f.newDeadline), s.unmarshalers())))
+		<-s.stalled
+		// right is starved by left until left is abandoned.
+		f.expire("L")
+		if err := <-result; err != nil {
+			panic(err)
+		}
+		if !e.IsRight() || e.Right()["foo"] != "foo" {
+			t.Errorf("incorrect: %#v", e)
+		}
+		// the abandoned branch fails at its next read, then exits.
+		close(s.release)
+		if err := <-s.readErr; err == nil {
+			t.Errorf("should fail to read input of an abandoned branch")
+		}
+	})
+
+	t.Run("stalled right", func(t *testing.T) {
+		var (
+			f fakeDeadlines
+			s = newStalls()
+			e Either[map[string]any, stallDecoder]
+		)
+		result := unmarshalAsync(input, &e, json.WithUnmarshalers(json.JoinUnmarshalers(eitherBranchUnmarshalers(f.newDeadline), s.unmarshalers())))
+		<-s.stalled
+		f.expire("R")
+		if err := <-result; err != nil {
+			panic(err)
+		}
+		if !e.IsLeft() || e.Left()["foo"] != "foo" {
+			t.Errorf("incorrect: %#v", e)
+		}
+		close(s.release)
+		<-s.readErr
+	})
+
+	t.Run("stalled after starved", func(t *testing.T) {
+		var (
+			f fakeDeadlines
+			s = newStalls()
+			e Either[stallDecoder, stallAfterReadDecoder]
+		)
+		result := unmarshalAsync(input, &e, json.WithUnmarshalers(json.JoinUnmarshalers(eitherBranchUnmarshalers(f.newDeadline), s.unmarshalers())))
+		<-s.stalled
+		f.expire("L")
+		// right has waited for input while left stalled; its deadline is re-armed once it reads.
+		<-s.stalled
+		f.expire("R")
+		err := <-result
+		if !errors.Is(err, ErrBranchTimeout) {
+			t.Errorf("should be ErrBranchTimeout, but is %v", err)
+		}
+		t.Logf("err = %v", err)
+		// arshaler_either_test.go:776: err = json: cannot unmarshal into Go play.eitherUnmarshaler after offset 98330: Either[L, R]: unmarshal failed for both L and R: l = (branch timeout: L expired), r = (branch timeout: R expired)
+		close(s.release)
+		<-s.readErr
+	})
+
+	t.Run("without deadline", func(t *testing.T) {
+		var (
+			s = newStalls()
+			e Either[stallDecoder, map[string]any]
+		)
+		result := unmarshalAsync(input, &e, json.WithUnmarshalers(s.unmarshalers()))
+		<-s.stalled
+		select {
+		case err := <-result:
+			t.Fatalf("should wait for the stalled branch, but returned with %v", err)
+		default:
+		}
+		close(s.release)
+		if err := <-result; err != nil {
+			panic(err)
+		}
+		if !e.IsLeft() {
+			t.Errorf("incorrect: %#v", e)
+		}
+	})
+
+	t.Run("WithEitherBranchTimeout", func(t *testing.T) {
+		s := newStalls()
+		defer close(s.release)
+		var e Either[stallDecoder, stallDecoder]
+		err := json.Unmarshal(
+			[]byte(`{"foo":"foo"}`),
+			&e,
+			json.WithUnmarshalers(json.JoinUnmarshalers(eitherBranchUnmarshalers(branchTimeout(time.Millisecond)), s.unmarshalers())),
+		)
+		if !errors.Is(err, ErrBranchTimeout) {
+			t.Errorf("should be ErrBranchTimeout, but is %v", err)
+		}
+		t.Logf("err = %v", err)
+		// arshaler_either_test.go:815: err = json: cannot unmarshal into Go play.eitherUnmarshaler after offset 12: Either[L, R]: unmarshal failed for both L and R: l = (branch timeout: L did not read its input in 1ms), r = (branch timeout: R did not read its input in 1ms)
+	})
+}
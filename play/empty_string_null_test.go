@@ -0,0 +1,62 @@
+package play
+
+import (
+	"encoding/json/jsontext"
+	"slices"
+	"testing"
+)
+
+// WithEmptyStringAsNull replaces an empty string value at any of pointers with null,
+// so that an Option, Und, pointer, number or bool target receiving "" from form-submitted JSON
+// decodes it as none or zero instead of failing.
+// Unlike WithNullStrings, it is scoped to pointers. A "*" token in pointers matches any token.
+func WithEmptyStringAsNull(pointers ...jsontext.Pointer) DecodeOption {
+	return func(c *decodeConfig) {
+		c.hooks = append(c.hooks, func(dec *jsontext.Decoder, tok jsontext.Token, emit func(jsontext.Token) error) error {
+			if tok.Kind() != '"' || tok.String() != "" || justReadName(dec) {
+				return emit(tok)
+			}
+			ptr := dec.StackPointer()
+			if !slices.ContainsFunc(pointers, func(p jsontext.Pointer) bool { return matchPointer(p, ptr) }) {
+				return emit(tok)
+			}
+			return emit(jsontext.Null)
+		})
+	}
+}
+
+func TestDecodeOption_EmptyStringAsNull(t *testing.T) {
+	type sample struct {
+		Age    Option[int] `json:"age"`
+		Score  *float64    `json:"score"`
+		Active bool        `json:"active"`
+		Name   string      `json:"name"`
+		Counts []int       `json:"counts"`
+	}
+	opt := WithEmptyStringAsNull("/age", "/score", "/active", "/counts/*")
+
+	var s sample
+	err := UnmarshalWith([]byte(`{"age":"","score":"","active":"","name":"","counts":[1,"",3]}`), &s, opt)
+	if err != nil {
+		panic(err)
+	}
+	if s.Age.IsSome() || s.Score != nil || s.Active || !slices.Equal(s.Counts, []int{1, 0, 3}) {
+		t.Errorf("incorrect: %#v", s)
+	}
+
+	err = UnmarshalWith([]byte(`{"age":21}`), &s, opt)
+	if err != nil {
+		panic(err)
+	}
+	if s.Age != Some(21) {
+		t.Errorf("not equal: expected(%#v) != actual(%#v)", Some(21), s.Age)
+	}
+
+	// not listed
+	err = UnmarshalWith([]byte(`{"age":"","name":""}`), &s, WithEmptyStringAsNull("/name"))
+	if err == nil {
+		t.Errorf("should fail for empty string at unlisted /age")
+	}
+	t.Logf("err = %v", err)
+	// empty_string_null_test.go:60: err = json: cannot unmarshal JSON string into Go int within "/age"
+}
@@ -0,0 +1,274 @@
+package play
+
+import (
+	"bytes"
+	"encoding/json/jsontext"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// Equal reads a single JSON document from each of a and b and reports whether they are semantically equal.
+// Object members are compared regardless of order, and numbers are compared by their exact values.
+//
+// Both are read in lockstep and Equal returns false at the first difference.
+// Objects are streamed while both have members in the same order;
+// once names diverge, the remaining members of both objects are buffered to be compared by name.
+func Equal(a, b io.Reader) (bool, error) {
+	da, db := jsontext.NewDecoder(a), jsontext.NewDecoder(b)
+	eq, err := equalValue(da, db)
+	if err != nil || !eq {
+		return eq, err
+	}
+	for _, dec := range []*jsontext.Decoder{da, db} {
+		if err := checkTrailingData(dec); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func equalValue(da, db *jsontext.Decoder) (bool, error) {
+	ka, kb := da.PeekKind(), db.PeekKind()
+	if ka == 0 || kb == 0 {
+		// let ReadToken report the error.
+		if _, err := da.ReadToken(); err != nil {
+			return false, err
+		}
+		_, err := db.ReadToken()
+		return false, err
+	}
+	if ka != kb {
+		return false, nil
+	}
+
+	ta, err := da.ReadToken()
+	if err != nil {
+		return false, err
+	}
+	tb, err := db.ReadToken()
+	if err != nil {
+		return false, err
+	}
+
+	switch ka {
+	case '[':
+		for {
+			endA, endB := da.PeekKind() == ']', db.PeekKind() == ']'
+			if endA || endB {
+				if endA != endB {
+					return false, nil
+				}
+				return readEnds(da, db)
+			}
+			if eq, err := equalValue(da, db); err != nil || !eq {
+				return eq, err
+			}
+		}
+	case '{':
+		for {
+			endA, endB := da.PeekKind() == '}', db.PeekKind() == '}'
+			if endA || endB {
+				if endA != endB {
+					return false, nil
+				}
+				return readEnds(da, db)
+			}
+			na, err := da.ReadToken()
+			if err != nil {
+				return false, err
+			}
+			nb, err := db.ReadToken()
+			if err != nil {
+				return false, err
+			}
+			if na.String() != nb.String() {
+				return equalRestMembers(da, db, na.String(), nb.String())
+			}
+			if eq, err := equalValue(da, db); err != nil || !eq {
+				return eq, err
+			}
+		}
+	case '0':
+		return equalNumber(ta.String(), tb.String())
+	case '"':
+		return ta.String() == tb.String(), nil
+	default:
+		// null, true and false are fully described by their kinds.
+		return true, nil
+	}
+}
+
+// maxRatExponent limits the exponent equalNumber compares by big.Rat,
+// which would otherwise expand 1e10000000 into ten million digits.
+const maxRatExponent = 1000
+
+// equalNumber reports whether number literals a and b have the same value.
+// They are compared exactly by big.Rat unless either exponent exceeds maxRatExponent;
+// such numbers are compared by their canonical literals instead, which is exact as well.
+func equalNumber(a, b string) (bool, error) {
+	if hasLargeExponent(a) || hasLargeExponent(b) {
+		return canonicalNumber(a) == canonicalNumber(b), nil
+	}
+	var ra, rb big.Rat
+	if _, ok := ra.SetString(a); !ok {
+		return false, fmt.Errorf("invalid number %s", a)
+	}
+	if _, ok := rb.SetString(b); !ok {
+		return false, fmt.Errorf("invalid number %s", b)
+	}
+	return ra.Cmp(&rb) == 0, nil
+}
+
+func hasLargeExponent(num string) bool {
+	_, exp, hasExp := strings.Cut(strings.ToLower(num), "e")
+	if !hasExp {
+		return false
+	}
+	e, err := strconv.Atoi(exp)
+	return err != nil || e > maxRatExponent || e < -maxRatExponent
+}
+
+// canonicalNumber returns num as <sign><digits>e<exponent>,
+// where digits has neither leading nor trailing zeros, or "0" for zero.
+func canonicalNumber(num string) string {
+	mantissa, exp, _ := strings.Cut(strings.ToLower(num), "e")
+	sign := ""
+	if m, ok := strings.CutPrefix(mantissa, "-"); ok {
+		sign, mantissa = "-", m
+	}
+	intPart, frac, _ := strings.Cut(mantissa, ".")
+	digits := strings.TrimLeft(intPart+frac, "0")
+	if digits == "" {
+		return "0"
+	}
+	trimmed := strings.TrimRight(digits, "0")
+	e, ok := new(big.Int).SetString(exp, 10)
+	if !ok {
+		e = new(big.Int)
+	}
+	e.Add(e, big.NewInt(int64(len(digits)-len(trimmed)-len(frac))))
+	return sign + trimmed + "e" + e.String()
+}
+
+func readEnds(da, db *jsontext.Decoder) (bool, error) {
+	if _, err := da.ReadToken(); err != nil {
+		return false, err
+	}
+	if _, err := db.ReadToken(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// equalRestMembers buffers the remaining members of the objects da and db are in,
+// where na and nb are names of the members just read, and compares them by name.
+func equalRestMembers(da, db *jsontext.Decoder, na, nb string) (bool, error) {
+	ma, err := readRestMembers(da, na)
+	if err != nil {
+		return false, err
+	}
+	mb, err := readRestMembers(db, nb)
+	if err != nil {
+		return false, err
+	}
+	if len(ma) != len(mb) {
+		return false, nil
+	}
+	for name, va := range ma {
+		vb, ok := mb[name]
+		if !ok {
+			return false, nil
+		}
+		eq, err := equalValue(jsontext.NewDecoder(bytes.NewReader(va)), jsontext.NewDecoder(bytes.NewReader(vb)))
+		if err != nil || !eq {
+			return eq, err
+		}
+	}
+	return true, nil
+}
+
+func readRestMembers(dec *jsontext.Decoder, name string) (map[string]jsontext.Value, error) {
+	m := make(map[string]jsontext.Value)
+	for {
+		val, err := dec.ReadValue()
+		if err != nil {
+			return nil, err
+		}
+		m[name] = val.Clone()
+		if dec.PeekKind() == '}' {
+			_, err := dec.ReadToken()
+			return m, err
+		}
+		tok, err := dec.ReadToken()
+		if err != nil {
+			return nil, err
+		}
+		name = tok.String()
+	}
+}
+
+func TestEqual(t *testing.T) {
+	type testCase struct {
+		a, b     string
+		expected bool
+	}
+	for _, tc := range []testCase{
+		{`{"foo":1,"bar":[true,null,"baz"]}`, `{"foo":1,"bar":[true,null,"baz"]}`, true},
+		{`{"foo":1,"bar":{"a":1,"b":2},"baz":3}`, `{"foo":1,"baz":3,"bar":{"b":2,"a":1}}`, true},
+		{`{"foo":1,"bar":2}`, `{"bar":2,"foo":1}`, true},
+		{`[1, 1.0, 1e2, -0]`, `[1.00,1,100,0]`, true},
+		{`12345678901234567890`, `12345678901234567891`, false},
+		{`[1e10000000, -1.50E-10000000, 0e99999999]`, `[10e9999999, -15e-10000001, 0]`, true},
+		{`1e10000000`, `1e10000001`, false},
+		{`1e1001`, `1` + strings.Repeat("0", 1001), true},
+		{`{"foo":1,"bar":2}`, `{"bar":2,"foo":3}`, false},
+		{`{"foo":1,"bar":2}`, `{"foo":1,"baz":2}`, false},
+		{`{"foo":1,"bar":2}`, `{"foo":1}`, false},
+		{`{"foo":1}`, `{"foo":1,"bar":2}`, false},
+		{`[1,2]`, `[1,2,3]`, false},
+		{`[1,2,3]`, `[1,2]`, false},
+		{`[1,2]`, `[2,1]`, false},
+		{`"1"`, `1`, false},
+		{`true`, `false`, false},
+		{`{}`, `[]`, false},
+	} {
+		t.Run(tc.a+" "+tc.b, func(t *testing.T) {
+			eq, err := Equal(strings.NewReader(tc.a), strings.NewReader(tc.b))
+			if err != nil {
+				panic(err)
+			}
+			if eq != tc.expected {
+				t.Errorf("not equal: expected(%t) != actual(%t)", tc.expected, eq)
+			}
+		})
+	}
+
+	// stops at the first difference without reading the rest.
+	var read int64
+	r := &countingReader{r: strings.NewReader(`[0,` + strings.Repeat("1,", 64<<10) + "1]"), n: &read}
+	eq, err := Equal(strings.NewReader(`[1]`), r)
+	if err != nil {
+		panic(err)
+	}
+	if eq {
+		t.Errorf("should not be equal")
+	}
+	if read >= 64<<10 {
+		t.Errorf("should stop partway, but read %d bytes", read)
+	}
+
+	_, err = Equal(strings.NewReader(`{"foo":`), strings.NewReader(`{"foo":1}`))
+	if err == nil {
+		t.Errorf("should cause an error")
+	}
+
+	_, err = Equal(strings.NewReader(`1`), strings.NewReader(`1 2`))
+	if !errors.Is(err, ErrTrailingData) {
+		t.Errorf("should be ErrTrailingData, but is %v", err)
+	}
+}
@@ -0,0 +1,242 @@
+package play
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// MakeExampleJSON returns a sample JSON document of T with a placeholder value for each field, e.g. for API documentation.
+//
+// Strings are "", numbers 0, booleans false, slices and maps have one element, and arrays as many as their length.
+// An Option is null, an Und struct field is omitted and an Either is the example of its left type.
+// Types with custom marshal methods are written as their zero value; any and recursive occurrences of a struct are null.
+// Field names honor json tags, including inlined structs.
+func MakeExampleJSON[T any]() ([]byte, error) {
+	return json.Marshal(exampleOf{t: reflect.TypeFor[T]()})
+}
+
+type exampleOf struct {
+	t reflect.Type
+}
+
+func (e exampleOf) MarshalJSONTo(enc *jsontext.Encoder) error {
+	return writeExample(enc, e.t, map[reflect.Type]bool{})
+}
+
+// eitherLike is implemented by Either. The left type is read from the signature of its Left method.
+type eitherLike interface {
+	IsLeft() bool
+	IsRight() bool
+}
+
+// writeExample writes an example of t to enc. visiting holds struct types being written to break recursion.
+func writeExample(enc *jsontext.Encoder, t reflect.Type, visiting map[reflect.Type]bool) error {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t.Implements(reflect.TypeFor[undefinable]()), t.Implements(reflect.TypeFor[noneable]()):
+		// An Und reaches here only where it can not be omitted.
+		return enc.WriteToken(jsontext.Null)
+	case t.Implements(reflect.TypeFor[eitherLike]()):
+		m, _ := t.MethodByName("Left")
+		return writeExample(enc, m.Type.Out(0), visiting)
+	case slices.ContainsFunc(marshalerTypes, func(it reflect.Type) bool { return t.Implements(it) || reflect.PointerTo(t).Implements(it) }):
+		return json.MarshalEncode(enc, reflect.New(t).Interface())
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return enc.WriteToken(jsontext.String(""))
+	case reflect.Bool:
+		return enc.WriteToken(jsontext.False)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return enc.WriteToken(jsontext.Int(0))
+	case reflect.Interface:
+		return enc.WriteToken(jsontext.Null)
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// byte slices and arrays are base64 strings.
+			return json.MarshalEncode(enc, reflect.New(t).Interface())
+		}
+		n := 1
+		if t.Kind() == reflect.Array {
+			n = t.Len()
+		}
+		if err := enc.WriteToken(jsontext.BeginArray); err != nil {
+			return err
+		}
+		for range n {
+			if err := writeExample(enc, t.Elem(), visiting); err != nil {
+				return err
+			}
+		}
+		return enc.WriteToken(jsontext.EndArray)
+	case reflect.Map:
+		key, err := exampleMapKey(t.Key())
+		if err != nil {
+			return err
+		}
+		if err := enc.WriteToken(jsontext.BeginObject); err != nil {
+			return err
+		}
+		if err := enc.WriteToken(jsontext.String(key)); err != nil {
+			return err
+		}
+		if err := writeExample(enc, t.Elem(), visiting); err != nil {
+			return err
+		}
+		return enc.WriteToken(jsontext.EndObject)
+	case reflect.Struct:
+		if visiting[t] {
+			return enc.WriteToken(jsontext.Null)
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+		if err := enc.WriteToken(jsontext.BeginObject); err != nil {
+			return err
+		}
+		if err := writeExampleFields(enc, t, visiting); err != nil {
+			return err
+		}
+		return enc.WriteToken(jsontext.EndObject)
+	}
+	return fmt.Errorf("example json: unsupported type %s", t)
+}
+
+func writeExampleFields(enc *jsontext.Encoder, t reflect.Type, visiting map[reflect.Type]bool) error {
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		name, inline, ok := jsonFieldName(sf)
+		if !ok {
+			continue
+		}
+		ft := sf.Type
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if inline {
+			if ft.Kind() != reflect.Struct {
+				continue
+			}
+			if err := writeExampleFields(enc, ft, visiting); err != nil {
+				return err
+			}
+			continue
+		}
+		if ft.Implements(reflect.TypeFor[undefinable]()) {
+			continue
+		}
+		if err := enc.WriteToken(jsontext.String(name)); err != nil {
+			return err
+		}
+		if err := writeExample(enc, sf.Type, visiting); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// exampleMapKey returns the object name for an example key of type t.
+func exampleMapKey(t reflect.Type) (string, error) {
+	switch t.Kind() {
+	case reflect.String:
+		return "", nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return "0", nil
+	}
+	bin, err := json.Marshal(reflect.New(t).Interface())
+	if err != nil {
+		return "", err
+	}
+	if jsontext.Value(bin).Kind() != '"' {
+		return "", fmt.Errorf("example json: unsupported map key type %s", t)
+	}
+	return strconv.Unquote(string(bin))
+}
+
+func TestMakeExampleJSON(t *testing.T) {
+	type Meta struct {
+		Version int `json:"version"`
+	}
+	type node struct {
+		Name     string  `json:"name"`
+		Children []*node `json:"children"`
+	}
+	type sample struct {
+		Meta     `json:",inline"`
+		Name     string              `json:"name"`
+		Count    int                 `json:"count,omitempty"`
+		Ratio    float64             `json:"ratio"`
+		Enabled  bool                `json:"enabled"`
+		Tags     []string            `json:"tags"`
+		Pair     [2]int              `json:"pair"`
+		Labels   map[string]int      `json:"labels"`
+		Nick     Option[string]      `json:"nick"`
+		Deleted  Und[string]         `json:"deleted"`
+		Value    Either[int, string] `json:"value"`
+		Values   []Either[Meta, int] `json:"values"`
+		Maybe    []Und[int]          `json:"maybe"`
+		At       time.Time           `json:"at"`
+		Data     []byte              `json:"data"`
+		Tree     *node               `json:"tree"`
+		Any      any                 `json:"any"`
+		Ignored  string              `json:"-"`
+		Untagged map[int]Option[bool]
+	}
+
+	bin, err := MakeExampleJSON[sample]()
+	if err != nil {
+		panic(err)
+	}
+	expected := `{"version":0,"name":"","count":0,"ratio":0,"enabled":false,"tags":[""],"pair":[0,0],` +
+		`"labels":{"":0},"nick":null,"value":0,"values":[{"version":0}],"maybe":[null],` +
+		`"at":"0001-01-01T00:00:00Z","data":"","tree":{"name":"","children":[null]},"any":null,"Untagged":{"0":null}}`
+	if string(bin) != expected {
+		t.Errorf("not equal:\nexpected(%s)\nactual(%s)", expected, string(bin))
+	}
+
+	var decoded sample
+	if err := json.Unmarshal(bin, &decoded); err != nil {
+		t.Errorf("example should be decodable: %v", err)
+	}
+
+	type testCase struct {
+		name     string
+		fn       func() ([]byte, error)
+		expected string
+	}
+	for _, tc := range []testCase{
+		{"string", MakeExampleJSON[string], `""`},
+		{"pointer", MakeExampleJSON[*[]int], `[0]`},
+		{"either", MakeExampleJSON[Either[[]string, int]], `[""]`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			bin, err := tc.fn()
+			if err != nil {
+				panic(err)
+			}
+			if string(bin) != tc.expected {
+				t.Errorf("not equal: expected(%s) != actual(%s)", tc.expected, string(bin))
+			}
+		})
+	}
+
+	_, err = MakeExampleJSON[struct{ C chan int }]()
+	if err == nil {
+		t.Errorf("should fail for chan")
+	}
+	t.Logf("err = %v", err)
+	// example_json_test.go:240: err = json: cannot marshal from Go play.exampleOf within "/C": example json: unsupported type chan int
+}
@@ -0,0 +1,155 @@
+package play
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// unionBranch is implemented by union types of this package.
+// branch returns the name of the resolved branch and the value it holds, or nil if it holds none.
+type unionBranch interface {
+	branch() (name string, held any)
+}
+
+func (e Either[L, R]) branch() (string, any) {
+	if e.IsLeft() {
+		return "left", e.Left()
+	}
+	return "right", e.Right()
+}
+
+func (o Option[V]) branch() (string, any) {
+	if o.IsNone() {
+		return "none", nil
+	}
+	return "some", o.Value()
+}
+
+func (u Und[V]) branch() (string, any) {
+	switch {
+	case u.IsUndefined():
+		return "undefined", nil
+	case u.IsNull():
+		return "null", nil
+	}
+	return "defined", u.Value()
+}
+
+// Explain marshals v and appends a report of which branch each Either, Option and Und in v resolved to,
+// one line per union prefixed by "//" so that the JSON part is kept intact:
+//
+//	{"v":5}
+//	// "/v": Either is right
+//
+// Map entries are sorted in both parts.
+// The report is for debugging, e.g. to see why an ambiguous Either decoded to the left.
+func Explain(v any) (string, error) {
+	bin, err := json.Marshal(v, json.Deterministic(true))
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	b.Write(bin)
+	explainValue(&b, reflect.ValueOf(v), "")
+	return b.String(), nil
+}
+
+func explainValue(b *strings.Builder, rv reflect.Value, ptr jsontext.Pointer) {
+	if !rv.IsValid() {
+		return
+	}
+	// Pointers are dereferenced first since a pointer to a union type also implements unionBranch.
+	if k := rv.Kind(); k != reflect.Pointer && k != reflect.Interface && rv.CanInterface() {
+		if u, ok := rv.Interface().(unionBranch); ok {
+			name, held := u.branch()
+			// Drop type arguments, which can be long for types with package paths.
+			typeName, _, _ := strings.Cut(rv.Type().Name(), "[")
+			fmt.Fprintf(b, "\n// %q: %s is %s", ptr, typeName, name)
+			explainValue(b, reflect.ValueOf(held), ptr)
+			return
+		}
+	}
+
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !rv.IsNil() {
+			explainValue(b, rv.Elem(), ptr)
+		}
+	case reflect.Struct:
+		for i := range rv.NumField() {
+			name, inline, ok := jsonFieldName(rv.Type().Field(i))
+			if !ok {
+				continue
+			}
+			if inline {
+				explainValue(b, rv.Field(i), ptr)
+				continue
+			}
+			explainValue(b, rv.Field(i), ptr.AppendToken(name))
+		}
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return
+		}
+		for i := range rv.Len() {
+			explainValue(b, rv.Index(i), ptr.AppendToken(fmt.Sprint(i)))
+		}
+	case reflect.Map:
+		keys := rv.MapKeys()
+		slices.SortFunc(keys, func(i, j reflect.Value) int { return strings.Compare(fmt.Sprint(i), fmt.Sprint(j)) })
+		for _, k := range keys {
+			explainValue(b, rv.MapIndex(k), ptr.AppendToken(fmt.Sprint(k)))
+		}
+	}
+}
+
+func TestExplain(t *testing.T) {
+	type inner struct {
+		Owner Option[string] `json:"owner"`
+	}
+	type sample struct {
+		V     Either[string, int]              `json:"v"`
+		Items []Either[inner, int]             `json:"items"`
+		Note  Und[string]                      `json:"note,omitzero"`
+		Tags  map[string]Option[int]           `json:"tags"`
+		Deep  *Either[Option[string], float64] `json:"deep"`
+	}
+
+	var s sample
+	err := json.Unmarshal(
+		[]byte(`{"v":5,"items":[{"owner":"foo"},1],"tags":{"b":null,"a":1},"deep":null}`),
+		&s,
+	)
+	if err != nil {
+		panic(err)
+	}
+	s.Deep = &Either[Option[string], float64]{}
+
+	explained, err := Explain(s)
+	if err != nil {
+		panic(err)
+	}
+	t.Logf("explained =\n%s", explained)
+
+	expected := `{"v":5,"items":[{"owner":"foo"},1],"tags":{"a":1,"b":null},"deep":null}
+// "/v": Either is right
+// "/items/0": Either is left
+// "/items/0/owner": Option is some
+// "/items/1": Either is right
+// "/note": Und is undefined
+// "/tags/a": Option is some
+// "/tags/b": Option is none
+// "/deep": Either is left
+// "/deep": Option is none`
+	if explained != expected {
+		t.Errorf("not equal:\nexpected:\n%s\nactual:\n%s", expected, explained)
+	}
+	if !strings.Contains(explained, `"/v": Either is right`) {
+		t.Errorf("right branch of /v should be reported")
+	}
+}
@@ -0,0 +1,90 @@
+package play
+
+import (
+	"encoding/json/jsontext"
+	"testing"
+)
+
+// WithFieldAliases renames object member names found in aliases, mapping a legacy name to the current one,
+// e.g. {"user_id": "userId"}, in objects at any depth, so both old and new payloads decode into the current struct.
+// A payload having both a legacy name and its current name in an object fails as a duplicate name.
+func WithFieldAliases(aliases map[string]string) DecodeOption {
+	return withFieldAliases(aliases, false)
+}
+
+// WithTopLevelFieldAliases is like WithFieldAliases but only renames members of the top-level object.
+func WithTopLevelFieldAliases(aliases map[string]string) DecodeOption {
+	return withFieldAliases(aliases, true)
+}
+
+func withFieldAliases(aliases map[string]string, topLevelOnly bool) DecodeOption {
+	return func(c *decodeConfig) {
+		c.hooks = append(c.hooks, func(dec *jsontext.Decoder, tok jsontext.Token, emit func(jsontext.Token) error) error {
+			if tok.Kind() != '"' || !justReadName(dec) || (topLevelOnly && dec.StackDepth() != 1) {
+				return emit(tok)
+			}
+			if current, ok := aliases[tok.String()]; ok {
+				return emit(jsontext.String(current))
+			}
+			return emit(tok)
+		})
+	}
+}
+
+func TestDecodeOption_FieldAliases(t *testing.T) {
+	type profile struct {
+		DisplayName string `json:"displayName"`
+	}
+	type user struct {
+		UserID  string  `json:"userId"`
+		Profile profile `json:"profile"`
+		Note    string  `json:"note"`
+	}
+	aliases := map[string]string{"user_id": "userId", "display_name": "displayName"}
+
+	type testCase struct {
+		name     string
+		in       string
+		opt      DecodeOption
+		expected user
+	}
+	for _, tc := range []testCase{
+		{
+			"current",
+			`{"userId":"u1","profile":{"displayName":"foo"}}`,
+			WithFieldAliases(aliases),
+			user{UserID: "u1", Profile: profile{"foo"}},
+		},
+		{
+			"legacy",
+			`{"user_id":"u1","profile":{"display_name":"foo"},"note":"user_id"}`,
+			WithFieldAliases(aliases),
+			user{UserID: "u1", Profile: profile{"foo"}, Note: "user_id"},
+		},
+		{
+			"top-level only",
+			`{"user_id":"u1","profile":{"display_name":"foo"}}`,
+			WithTopLevelFieldAliases(aliases),
+			user{UserID: "u1"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var u user
+			err := UnmarshalWith([]byte(tc.in), &u, tc.opt)
+			if err != nil {
+				panic(err)
+			}
+			if u != tc.expected {
+				t.Errorf("not equal: expected(%#v) != actual(%#v)", tc.expected, u)
+			}
+		})
+	}
+
+	var u user
+	err := UnmarshalWith([]byte(`{"user_id":"u1","userId":"u2"}`), &u, WithFieldAliases(aliases))
+	if err == nil {
+		t.Errorf("should fail for both legacy and current names")
+	}
+	t.Logf("err = %v", err)
+	// field_aliases_test.go:88: err = jsontext: duplicate object member name "userId"
+}
@@ -0,0 +1,81 @@
+package play
+
+import (
+	"encoding/json/jsontext"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+var ErrFieldOrder = errors.New("field out of order")
+
+// WithRequiredFieldOrder rejects a top-level object whose members named in order do not appear in that order.
+// The error wraps ErrFieldOrder and reports the first out-of-order member.
+// Members not named in order may appear anywhere, and absent members are not checked.
+func WithRequiredFieldOrder(order []string) DecodeOption {
+	return func(c *decodeConfig) {
+		last := -1
+		c.hooks = append(c.hooks, func(dec *jsontext.Decoder, tok jsontext.Token, emit func(jsontext.Token) error) error {
+			if dec.StackDepth() != 1 || !justReadName(dec) {
+				return emit(tok)
+			}
+			name := tok.String()
+			i := slices.Index(order, name)
+			if i < 0 {
+				return emit(tok)
+			}
+			if i < last {
+				return fmt.Errorf("%w: %q must precede %q", ErrFieldOrder, name, order[last])
+			}
+			last = i
+			return emit(tok)
+		})
+	}
+}
+
+func TestDecodeOption_RequiredFieldOrder(t *testing.T) {
+	type sample struct {
+		Alg     string `json:"alg"`
+		Kid     string `json:"kid"`
+		Payload struct {
+			Z int `json:"z"`
+			A int `json:"a"`
+		} `json:"payload"`
+		Extra string `json:"extra"`
+	}
+	opt := WithRequiredFieldOrder([]string{"alg", "kid", "payload"})
+
+	for _, in := range []string{
+		`{"alg":"HS256","kid":"1","payload":{"z":1,"a":2}}`,
+		`{"extra":"x","alg":"HS256","payload":{"a":2,"z":1}}`,
+		`{"kid":"1"}`,
+		`{}`,
+	} {
+		var s sample
+		err := UnmarshalWith([]byte(in), &s, opt)
+		if err != nil {
+			t.Errorf("%s: should not cause an error: %v", in, err)
+		}
+	}
+
+	type testCase struct {
+		in       string
+		expected string
+	}
+	for _, tc := range []testCase{
+		{`{"kid":"1","alg":"HS256","payload":{}}`, `field out of order: "alg" must precede "kid"`},
+		{`{"alg":"HS256","payload":{},"extra":"","kid":"1"}`, `field out of order: "kid" must precede "payload"`},
+	} {
+		t.Run(tc.in, func(t *testing.T) {
+			var s sample
+			err := UnmarshalWith([]byte(tc.in), &s, opt)
+			if !errors.Is(err, ErrFieldOrder) {
+				t.Fatalf("should be ErrFieldOrder, but is %v", err)
+			}
+			if err.Error() != tc.expected {
+				t.Errorf("not equal: expected(%s) != actual(%s)", tc.expected, err)
+			}
+		})
+	}
+}
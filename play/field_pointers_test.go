@@ -0,0 +1,187 @@
+package play
+
+import (
+	"encoding"
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"maps"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// FieldPointers returns the JSON pointer for each Go field reachable from the struct type of v,
+// keyed by the path of Go field names joined by ".", e.g. "User.Name" -> "/user/name".
+// Fields of embedded structs without a JSON name are promoted as Go does: "Base.ID" is keyed by "ID".
+// Elements of slices, arrays and maps are denoted by "*" in the pointer, e.g. "Items.Name" -> "/items/*/name".
+//
+// v may be a struct or a pointer to a struct.
+// Types with custom marshal methods are treated as leaves since their wire shape can not be known.
+func FieldPointers(v any) map[string]jsontext.Pointer {
+	m := make(map[string]jsontext.Pointer)
+	collectFieldPointers(m, reflect.TypeOf(v), "", "", make(map[reflect.Type]bool))
+	return m
+}
+
+var customMarshalerTypes = []reflect.Type{
+	reflect.TypeFor[json.MarshalerTo](),
+	reflect.TypeFor[json.Marshaler](),
+	reflect.TypeFor[encoding.TextMarshaler](),
+}
+
+func collectFieldPointers(m map[string]jsontext.Pointer, t reflect.Type, goPath string, ptr jsontext.Pointer, visiting map[reflect.Type]bool) {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil {
+		return
+	}
+	for _, it := range customMarshalerTypes {
+		if reflect.PointerTo(t).Implements(it) {
+			return
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return
+		}
+		collectFieldPointers(m, t.Elem(), goPath, ptr.AppendToken("*"), visiting)
+		return
+	case reflect.Map:
+		collectFieldPointers(m, t.Elem(), goPath, ptr.AppendToken("*"), visiting)
+		return
+	case reflect.Struct:
+	default:
+		return
+	}
+
+	// guard against recursive types.
+	if visiting[t] {
+		return
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		name, inline, ok := jsonFieldName(sf)
+		if !ok {
+			continue
+		}
+		if inline {
+			collectFieldPointers(m, sf.Type, goPath, ptr, visiting)
+			continue
+		}
+		key := sf.Name
+		if goPath != "" {
+			key = goPath + "." + sf.Name
+		}
+		p := ptr.AppendToken(name)
+		m[key] = p
+		collectFieldPointers(m, sf.Type, key, p, visiting)
+	}
+}
+
+// jsonFieldName returns the JSON object name of sf and whether sf is inlined into its parent.
+// ok is false if sf is not serialized as a member.
+func jsonFieldName(sf reflect.StructField) (name string, inline bool, ok bool) {
+	tag, _ := sf.Tag.Lookup("json")
+	if tag == "-" {
+		return "", false, false
+	}
+	if !sf.IsExported() && !sf.Anonymous {
+		return "", false, false
+	}
+
+	name = sf.Name
+	hasName := false
+	if n, rest, _ := strings.Cut(tag, ","); n != "" {
+		name, tag, hasName = n, ","+rest, true
+	}
+	for opt := range strings.SplitSeq(tag, ",") {
+		switch opt {
+		case "inline":
+			return "", true, true
+		case "unknown":
+			return "", false, false
+		}
+	}
+
+	t := sf.Type
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if sf.Anonymous && !hasName && t.Kind() == reflect.Struct {
+		return "", true, true
+	}
+	if !sf.IsExported() {
+		return "", false, false
+	}
+	return name, false, true
+}
+
+func TestFieldPointers(t *testing.T) {
+	type Base struct {
+		ID   string `json:"id"`
+		Kind string
+	}
+	type item struct {
+		Name  string `json:"name"`
+		Price int    `json:"price,omitzero"`
+	}
+	type user struct {
+		Base
+		Name    string          `json:"name"`
+		Path    string          `json:"a/b~c"`
+		Ignored string          `json:"-"`
+		Items   []item          `json:"items"`
+		Tags    map[string]item `json:"tags"`
+		Parent  *user           `json:"parent"`
+		Opt     Option[item]    `json:"opt"`
+		hidden  string
+	}
+	type sample struct {
+		User user `json:"user"`
+	}
+
+	expected := map[string]jsontext.Pointer{
+		"User":             "/user",
+		"User.ID":          "/user/id",
+		"User.Kind":        "/user/Kind",
+		"User.Name":        "/user/name",
+		"User.Path":        "/user/a~1b~0c",
+		"User.Items":       "/user/items",
+		"User.Items.Name":  "/user/items/*/name",
+		"User.Items.Price": "/user/items/*/price",
+		"User.Tags":        "/user/tags",
+		"User.Tags.Name":   "/user/tags/*/name",
+		"User.Tags.Price":  "/user/tags/*/price",
+		"User.Parent":      "/user/parent",
+		"User.Opt":         "/user/opt",
+	}
+	actual := FieldPointers(&sample{})
+	if !maps.Equal(expected, actual) {
+		t.Errorf("not equal:\nexpected(%#v)\n!=\nactual(%#v)", expected, actual)
+	}
+
+	// the pointers actually point to the values.
+	s := sample{User: user{Base: Base{ID: "foo"}, Items: []item{{Name: "bar"}}}}
+	bin, err := json.Marshal(s)
+	if err != nil {
+		panic(err)
+	}
+	for _, p := range []jsontext.Pointer{actual["User.ID"], jsontext.Pointer(strings.ReplaceAll(string(actual["User.Items.Name"]), "*", "0"))} {
+		var found string
+		err := ReadJSONAt(jsontext.NewDecoder(strings.NewReader(string(bin))), p, func(dec *jsontext.Decoder) error {
+			return json.UnmarshalDecode(dec, &found)
+		})
+		if err != nil {
+			t.Errorf("%q: %v", p, err)
+		}
+		if found == "" {
+			t.Errorf("%q: not found", p)
+		}
+	}
+}
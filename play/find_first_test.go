@@ -0,0 +1,236 @@
+package play
+
+import (
+	"bytes"
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// FindFirst reads a value from dec and returns the first scalar value, with its pointer, for which pred reports true.
+// Values are visited in document order as tokens are read; only the value being tested is read as a whole,
+// so objects and arrays are never buffered. To test objects and arrays as well, use FindFirstWithContainers.
+// Object names are not visited.
+//
+// It returns false if no value matched. Reading stops right after the matched value;
+// the rest of dec, including the remainder of the enclosing containers, is left unread.
+func FindFirst(dec *jsontext.Decoder, pred func(ptr jsontext.Pointer, v jsontext.Value) bool) (jsontext.Pointer, jsontext.Value, bool, error) {
+	return findFirst(dec, pred, false, dec.StackDepth())
+}
+
+// FindFirstWithContainers is FindFirst which also passes objects and arrays to pred, before their elements,
+// so that pred can test a container by more than one of its members, e.g. the first object with status=error.
+//
+// Each object and array is read as a whole to be tested, and its elements are then searched from the buffer.
+// Thus reading stops right after the outermost container enclosing the match, rather than after the match.
+func FindFirstWithContainers(dec *jsontext.Decoder, pred func(ptr jsontext.Pointer, v jsontext.Value) bool) (jsontext.Pointer, jsontext.Value, bool, error) {
+	return findFirst(dec, pred, true, dec.StackDepth())
+}
+
+// findFirst reads tokens from dec until its stack depth returns to depth.
+func findFirst(
+	dec *jsontext.Decoder,
+	pred func(ptr jsontext.Pointer, v jsontext.Value) bool,
+	containers bool,
+	depth int,
+) (jsontext.Pointer, jsontext.Value, bool, error) {
+	for {
+		kind, length := dec.StackIndex(dec.StackDepth())
+		switch next := dec.PeekKind(); {
+		case next == '}' || next == ']' || next == 0,
+			!containers && (next == '{' || next == '['),
+			// an object name. PeekKind hides errors; let ReadToken report them.
+			kind == '{' && length%2 == 0:
+			if _, err := dec.ReadToken(); err != nil {
+				return "", nil, false, err
+			}
+		default:
+			v, err := dec.ReadValue()
+			if err != nil {
+				return "", nil, false, err
+			}
+			// StackPointer points to the value just read.
+			ptr := dec.StackPointer()
+			if pred(ptr, v) {
+				// v is only valid until the next read from dec.
+				return ptr, v.Clone(), true, nil
+			}
+			if next == '{' || next == '[' {
+				inner := jsontext.NewDecoder(bytes.NewReader(v))
+				if _, err := inner.ReadToken(); err != nil {
+					return "", nil, false, err
+				}
+				innerPtr, innerV, ok, err := findFirst(
+					inner,
+					func(p jsontext.Pointer, v jsontext.Value) bool { return pred(ptr+p, v) },
+					true,
+					0,
+				)
+				if err != nil {
+					return "", nil, false, err
+				}
+				if ok {
+					return ptr + innerPtr, innerV, true, nil
+				}
+			}
+		}
+		if dec.StackDepth() == depth {
+			return "", nil, false, nil
+		}
+	}
+}
+
+func TestFindFirst(t *testing.T) {
+	const input = `{
+    "meta": {"count": 3, "limit": 100},
+    "items": [
+        {"id": 1, "scores": [10, 99.5]},
+        {"id": 2, "scores": [50, 101, 150]},
+        {"id": 300}
+    ]
+} {"next": true}`
+
+	greaterThan100 := func(ptr jsontext.Pointer, v jsontext.Value) bool {
+		if v.Kind() != '0' {
+			return false
+		}
+		f, err := strconv.ParseFloat(string(v), 64)
+		return err == nil && f > 100
+	}
+
+	dec := jsontext.NewDecoder(strings.NewReader(input))
+	ptr, v, ok, err := FindFirst(dec, greaterThan100)
+	if err != nil {
+		panic(err)
+	}
+	if !ok || ptr != "/items/1/scores/1" || string(v) != "101" {
+		t.Errorf("incorrect: ptr = %q, v = %s, ok = %t", ptr, v, ok)
+	}
+	// the rest of the stream is left as is, from right after the match.
+	next, err := dec.ReadToken()
+	if err != nil {
+		panic(err)
+	}
+	if next.Kind() != '0' || next.String() != "150" {
+		t.Errorf("should stop right after the match, but next token is %s", next)
+	}
+
+	// input past the match is never read.
+	ptr, v, ok, err = FindFirst(jsontext.NewDecoder(strings.NewReader(`{"a":[1,{"b":200},[`+"\x00garbage")), greaterThan100)
+	if err != nil {
+		panic(err)
+	}
+	if !ok || ptr != "/a/1/b" || string(v) != "200" {
+		t.Errorf("incorrect: ptr = %q, v = %s, ok = %t", ptr, v, ok)
+	}
+
+	type testCase struct {
+		name     string
+		pred     func(ptr jsontext.Pointer, v jsontext.Value) bool
+		expected jsontext.Pointer
+		ok       bool
+	}
+	for _, tc := range []testCase{
+		{
+			"object by member",
+			func(ptr jsontext.Pointer, v jsontext.Value) bool { return ptr.LastToken() == "id" && string(v) == "2" },
+			"/items/1/id",
+			true,
+		},
+		{"first scalar", func(ptr jsontext.Pointer, v jsontext.Value) bool { return true }, "/meta/count", true},
+		{"by pointer", func(ptr jsontext.Pointer, v jsontext.Value) bool { return ptr.LastToken() == "limit" }, "/meta/limit", true},
+		{"none", func(ptr jsontext.Pointer, v jsontext.Value) bool { return v.Kind() == 'n' }, "", false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ptr, _, ok, err := FindFirst(jsontext.NewDecoder(strings.NewReader(input)), tc.pred)
+			if err != nil {
+				panic(err)
+			}
+			if ptr != tc.expected || ok != tc.ok {
+				t.Errorf("not equal: expected(%q, %t) != actual(%q, %t)", tc.expected, tc.ok, ptr, ok)
+			}
+		})
+	}
+
+	_, _, _, err = FindFirst(jsontext.NewDecoder(strings.NewReader(`{"a":[1,`)), greaterThan100)
+	if err == nil {
+		t.Errorf("should fail for truncated input")
+	}
+}
+
+func TestFindFirstWithContainers(t *testing.T) {
+	const input = `{
+    "jobs": [
+        {"id": 1, "status": "error", "code": 400},
+        {"id": 2, "status": "ok", "code": 500},
+        {"id": 3, "status": "error", "code": 503, "tags": ["retry", "alert"]}
+    ]
+} {"next": true}`
+
+	serverError := func(ptr jsontext.Pointer, v jsontext.Value) bool {
+		if v.Kind() != '{' {
+			return false
+		}
+		var job struct {
+			Status string `json:"status"`
+			Code   int    `json:"code"`
+		}
+		return json.Unmarshal(v, &job) == nil && job.Status == "error" && job.Code >= 500
+	}
+
+	dec := jsontext.NewDecoder(strings.NewReader(input))
+	ptr, v, ok, err := FindFirstWithContainers(dec, serverError)
+	if err != nil {
+		panic(err)
+	}
+	if !ok || ptr != "/jobs/2" || !strings.Contains(string(v), `"id": 3`) {
+		t.Errorf("incorrect: ptr = %q, v = %s, ok = %t", ptr, v, ok)
+	}
+	// the outermost container enclosing the match is read as a whole.
+	next, err := dec.ReadToken()
+	if err != nil {
+		panic(err)
+	}
+	if next.Kind() != '{' {
+		t.Errorf("should stop after the document, but next token is %s", next)
+	}
+
+	type testCase struct {
+		name     string
+		pred     func(ptr jsontext.Pointer, v jsontext.Value) bool
+		expected jsontext.Pointer
+		ok       bool
+	}
+	for _, tc := range []testCase{
+		{"root", func(ptr jsontext.Pointer, v jsontext.Value) bool { return true }, "", true},
+		{"array", func(ptr jsontext.Pointer, v jsontext.Value) bool { return v.Kind() == '[' }, "/jobs", true},
+		{
+			"nested scalar",
+			func(ptr jsontext.Pointer, v jsontext.Value) bool { return string(v) == `"alert"` },
+			"/jobs/2/tags/1",
+			true,
+		},
+		{"none", func(ptr jsontext.Pointer, v jsontext.Value) bool { return v.Kind() == 'n' }, "", false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ptr, _, ok, err := FindFirstWithContainers(jsontext.NewDecoder(strings.NewReader(input)), tc.pred)
+			if err != nil {
+				panic(err)
+			}
+			if ptr != tc.expected || ok != tc.ok {
+				t.Errorf("not equal: expected(%q, %t) != actual(%q, %t)", tc.expected, tc.ok, ptr, ok)
+			}
+		})
+	}
+
+	// FindFirst does not pass objects and arrays to pred.
+	_, _, ok, err = FindFirst(jsontext.NewDecoder(strings.NewReader(input)), serverError)
+	if err != nil {
+		panic(err)
+	}
+	if ok {
+		t.Errorf("FindFirst should not pass objects to pred")
+	}
+}
@@ -0,0 +1,85 @@
+package play
+
+import (
+	"encoding/json/jsontext"
+	"iter"
+	"maps"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// FindStrings reads a value from dec and yields the pointer and content of every string value matching re.
+// Object names are not matched.
+//
+// Iteration stops at the end of the value, or at the first read error since iter.Seq2 can not carry it.
+func FindStrings(dec *jsontext.Decoder, re *regexp.Regexp) iter.Seq2[jsontext.Pointer, string] {
+	return findStrings(dec, re, false)
+}
+
+// FindStringsWithNames is like FindStrings but also matches object names.
+// For a name, the pointer points to the member it names.
+func FindStringsWithNames(dec *jsontext.Decoder, re *regexp.Regexp) iter.Seq2[jsontext.Pointer, string] {
+	return findStrings(dec, re, true)
+}
+
+func findStrings(dec *jsontext.Decoder, re *regexp.Regexp, names bool) iter.Seq2[jsontext.Pointer, string] {
+	return func(yield func(jsontext.Pointer, string) bool) {
+		depth := dec.StackDepth()
+		for {
+			tok, err := dec.ReadToken()
+			if err != nil {
+				return
+			}
+			if tok.Kind() == '"' && (names || !justReadName(dec)) {
+				if s := tok.String(); re.MatchString(s) {
+					if !yield(dec.StackPointer(), s) {
+						return
+					}
+				}
+			}
+			if dec.StackDepth() == depth {
+				return
+			}
+		}
+	}
+}
+
+func TestFindStrings(t *testing.T) {
+	input := `{
+    "owner": "alice@example.com",
+    "members": [
+        {"name": "bob", "contact": {"email": "bob@example.com"}},
+        {"name": "carol", "contact": {"phone": "000-0000"}}
+    ],
+    "dave@example.com": "former owner"
+}`
+	re := regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[a-z]+$`)
+
+	found := maps.Collect(FindStrings(jsontext.NewDecoder(strings.NewReader(input)), re))
+	expected := map[jsontext.Pointer]string{
+		"/owner":                   "alice@example.com",
+		"/members/0/contact/email": "bob@example.com",
+	}
+	if !maps.Equal(expected, found) {
+		t.Errorf("not equal:\nexpected(%#v)\n!=\nactual(%#v)", expected, found)
+	}
+
+	found = maps.Collect(FindStringsWithNames(jsontext.NewDecoder(strings.NewReader(input)), re))
+	expected["/dave@example.com"] = "dave@example.com"
+	if !maps.Equal(expected, found) {
+		t.Errorf("not equal:\nexpected(%#v)\n!=\nactual(%#v)", expected, found)
+	}
+
+	// breaking out of the loop stops reading.
+	dec := jsontext.NewDecoder(strings.NewReader(input))
+	for p := range FindStrings(dec, re) {
+		if p != "/owner" {
+			t.Errorf("incorrect: %q", p)
+		}
+		break
+	}
+	if dec.StackPointer() != "/owner" {
+		t.Errorf("should stop at the first match, but is at %q", dec.StackPointer())
+	}
+}
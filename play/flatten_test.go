@@ -0,0 +1,277 @@
+package play
+
+import (
+	"bytes"
+	"encoding/json/jsontext"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var ErrFlattenConflict = errors.New("flatten conflict")
+
+// Flatten reads a value from dec and writes a single-level object to enc
+// whose names are paths to leaf values joined by sep.
+// Array indices are written as decimal numbers.
+// Empty objects and arrays are kept as leaf values so that Unflatten can restore them.
+func Flatten(dec *jsontext.Decoder, enc *jsontext.Encoder, sep string) error {
+	if err := enc.WriteToken(jsontext.BeginObject); err != nil {
+		return err
+	}
+	var path []string
+	var flatten func() error
+	flatten = func() error {
+		switch dec.PeekKind() {
+		case '{', '[':
+			begin, err := dec.ReadToken()
+			if err != nil {
+				return err
+			}
+			end := byte('}')
+			if begin.Kind() == '[' {
+				end = ']'
+			}
+			if byte(dec.PeekKind()) == end {
+				if _, err := dec.ReadToken(); err != nil {
+					return err
+				}
+				if err := enc.WriteToken(jsontext.String(strings.Join(path, sep))); err != nil {
+					return err
+				}
+				return enc.WriteValue(jsontext.Value(string(begin.Kind()) + string(end)))
+			}
+			for i := 0; byte(dec.PeekKind()) != end; i++ {
+				name := strconv.Itoa(i)
+				if begin.Kind() == '{' {
+					tok, err := dec.ReadToken()
+					if err != nil {
+						return err
+					}
+					name = tok.String()
+				}
+				path = append(path, name)
+				if err := flatten(); err != nil {
+					return err
+				}
+				path = path[:len(path)-1]
+			}
+			_, err = dec.ReadToken()
+			return err
+		default:
+			val, err := dec.ReadValue()
+			if err != nil {
+				return err
+			}
+			if err := enc.WriteToken(jsontext.String(strings.Join(path, sep))); err != nil {
+				return err
+			}
+			return enc.WriteValue(val)
+		}
+	}
+	if err := flatten(); err != nil {
+		return err
+	}
+	return enc.WriteToken(jsontext.EndObject)
+}
+
+type flatNode struct {
+	leaf     jsontext.Value
+	names    []string // insertion order
+	children map[string]*flatNode
+}
+
+func (n *flatNode) child(name string) *flatNode {
+	if n.children == nil {
+		n.children = make(map[string]*flatNode)
+	}
+	c, ok := n.children[name]
+	if !ok {
+		c = new(flatNode)
+		n.children[name] = c
+		n.names = append(n.names, name)
+	}
+	return c
+}
+
+// isArray reports whether names of n are exactly 0 to len-1, in any order.
+func (n *flatNode) isArray() bool {
+	if len(n.names) == 0 {
+		return false
+	}
+	seen := make([]bool, len(n.names))
+	for _, name := range n.names {
+		if name == "" || strings.TrimLeftFunc(name, func(r rune) bool { return '0' <= r && r <= '9' }) != "" {
+			return false
+		}
+		if len(name) > 1 && name[0] == '0' {
+			return false
+		}
+		i, err := strconv.Atoi(name)
+		if err != nil || i >= len(seen) || seen[i] {
+			return false
+		}
+		seen[i] = true
+	}
+	return true
+}
+
+func (n *flatNode) encode(enc *jsontext.Encoder) error {
+	if n.leaf != nil {
+		return enc.WriteValue(n.leaf)
+	}
+	if n.isArray() {
+		if err := enc.WriteToken(jsontext.BeginArray); err != nil {
+			return err
+		}
+		for i := range len(n.names) {
+			if err := n.children[strconv.Itoa(i)].encode(enc); err != nil {
+				return err
+			}
+		}
+		return enc.WriteToken(jsontext.EndArray)
+	}
+	if err := enc.WriteToken(jsontext.BeginObject); err != nil {
+		return err
+	}
+	for _, name := range n.names {
+		if err := enc.WriteToken(jsontext.String(name)); err != nil {
+			return err
+		}
+		if err := n.children[name].encode(enc); err != nil {
+			return err
+		}
+	}
+	return enc.WriteToken(jsontext.EndObject)
+}
+
+// Unflatten is the inverse of Flatten.
+// It reads a single-level object from dec, splits each name by sep and writes the nested structure to enc.
+// Objects whose names are exactly 0 to n-1 are written as arrays.
+//
+// Unflatten must buffer the entire input since members sharing a prefix may not be adjacent.
+// A name that is used both as a leaf and as a prefix of another name is reported as ErrFlattenConflict.
+func Unflatten(dec *jsontext.Decoder, enc *jsontext.Encoder, sep string) error {
+	if dec.PeekKind() != '{' {
+		return fmt.Errorf("unflatten: expected object but got %q", dec.PeekKind().String())
+	}
+	if _, err := dec.ReadToken(); err != nil {
+		return err
+	}
+	var root *flatNode
+	for dec.PeekKind() != '}' {
+		tok, err := dec.ReadToken()
+		if err != nil {
+			return err
+		}
+		name := tok.String()
+		val, err := dec.ReadValue()
+		if err != nil {
+			return err
+		}
+		if root == nil {
+			root = new(flatNode)
+		}
+		if name == "" {
+			// the root itself is a leaf.
+			if len(root.names) > 0 {
+				return fmt.Errorf("%w: %q is both a leaf and a prefix", ErrFlattenConflict, name)
+			}
+			root.leaf = val.Clone()
+			continue
+		}
+		if root.leaf != nil {
+			return fmt.Errorf("%w: %q is both a leaf and a prefix", ErrFlattenConflict, "")
+		}
+		n := root
+		segments := strings.Split(name, sep)
+		for i, seg := range segments {
+			n = n.child(seg)
+			if n.leaf != nil {
+				return fmt.Errorf("%w: %q is both a leaf and a prefix", ErrFlattenConflict, strings.Join(segments[:i+1], sep))
+			}
+		}
+		if len(n.names) > 0 {
+			return fmt.Errorf("%w: %q is both a leaf and a prefix", ErrFlattenConflict, name)
+		}
+		n.leaf = val.Clone()
+	}
+	if _, err := dec.ReadToken(); err != nil {
+		return err
+	}
+	if root == nil {
+		return enc.WriteValue(jsontext.Value(`{}`))
+	}
+	return root.encode(enc)
+}
+
+func TestFlatten(t *testing.T) {
+	const input = `{"foo":null,"baz":["qux",123,"quux",[{"corge":null}]],"empty":{},"none":[],"a":{"b":{"c":true}}}`
+
+	var flat bytes.Buffer
+	err := Flatten(
+		jsontext.NewDecoder(strings.NewReader(input)),
+		jsontext.NewEncoder(&flat),
+		".",
+	)
+	if err != nil {
+		panic(err)
+	}
+	expectedFlat := `{"foo":null,"baz.0":"qux","baz.1":123,"baz.2":"quux","baz.3.0.corge":null,"empty":{},"none":[],"a.b.c":true}`
+	if got := strings.TrimSpace(flat.String()); got != expectedFlat {
+		t.Errorf("not equal:\nexpected = %s\nactual   = %s", expectedFlat, got)
+	}
+
+	var nested bytes.Buffer
+	err = Unflatten(
+		jsontext.NewDecoder(&flat),
+		jsontext.NewEncoder(&nested),
+		".",
+	)
+	if err != nil {
+		panic(err)
+	}
+	if got := strings.TrimSpace(nested.String()); got != input {
+		t.Errorf("not equal:\nexpected = %s\nactual   = %s", input, got)
+	}
+}
+
+func TestUnflatten(t *testing.T) {
+	type testCase struct {
+		in       string
+		expected string
+		conflict bool
+	}
+	for _, tc := range []testCase{
+		{`{"a/1":"b","a/0":"a"}`, `{"a":["a","b"]}`, false},
+		{`{"a/0":"a","a/2":"c"}`, `{"a":{"0":"a","2":"c"}}`, false},
+		{`{"x/y":1,"z":2,"x/w":3}`, `{"x":{"y":1,"w":3},"z":2}`, false},
+		{`{}`, `{}`, false},
+		{`{"a":1,"a/b":2}`, ``, true},
+		{`{"a/b":2,"a":1}`, ``, true},
+		{`{"a/b/c":2,"a/b":1}`, ``, true},
+	} {
+		t.Run(tc.in, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := Unflatten(
+				jsontext.NewDecoder(strings.NewReader(tc.in)),
+				jsontext.NewEncoder(&buf),
+				"/",
+			)
+			if tc.conflict {
+				if !errors.Is(err, ErrFlattenConflict) {
+					t.Errorf("should be ErrFlattenConflict, but is %v", err)
+				}
+				t.Logf("err = %v", err)
+				return
+			}
+			if err != nil {
+				panic(err)
+			}
+			if got := strings.TrimSpace(buf.String()); got != tc.expected {
+				t.Errorf("not equal: expected(%s) != actual(%s)", tc.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package play
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+var (
+	_ json.MarshalerTo     = FlexTime{}
+	_ json.UnmarshalerFrom = (*FlexTime)(nil)
+)
+
+// FlexTimeUnixSeconds is a pseudo layout for FlexTime accepting a JSON number of seconds since the Unix epoch.
+const FlexTimeUnixSeconds = "unix seconds"
+
+// DefaultFlexTimeLayouts is layouts FlexTime tries in order unless overridden by WithFlexTimeLayouts.
+var DefaultFlexTimeLayouts = []string{time.RFC3339Nano, time.RFC3339, time.DateOnly, FlexTimeUnixSeconds}
+
+// FlexTime is time.Time which unmarshals from any of multiple formats, succeeding on the first matching layout.
+// It always marshals to RFC 3339.
+type FlexTime struct {
+	time.Time
+}
+
+func (t FlexTime) MarshalJSONTo(enc *jsontext.Encoder) error {
+	return enc.WriteToken(jsontext.String(t.Time.Format(time.RFC3339Nano)))
+}
+
+func (t *FlexTime) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	return t.unmarshalJSONFrom(dec, DefaultFlexTimeLayouts)
+}
+
+// WithFlexTimeLayouts returns options making FlexTime try layouts in order instead of DefaultFlexTimeLayouts.
+// Use FlexTimeUnixSeconds to accept numbers.
+func WithFlexTimeLayouts(layouts ...string) json.Options {
+	return json.WithUnmarshalers(json.UnmarshalFromFunc(func(dec *jsontext.Decoder, t *FlexTime) error {
+		return t.unmarshalJSONFrom(dec, layouts)
+	}))
+}
+
+func (t *FlexTime) unmarshalJSONFrom(dec *jsontext.Decoder, layouts []string) error {
+	tok, err := dec.ReadToken()
+	if err != nil {
+		return err
+	}
+	in := tok.String()
+	switch tok.Kind() {
+	case '"':
+		in = strconv.Quote(in)
+		for _, layout := range layouts {
+			if layout == FlexTimeUnixSeconds {
+				continue
+			}
+			if parsed, err := time.Parse(layout, tok.String()); err == nil {
+				t.Time = parsed
+				return nil
+			}
+		}
+	case '0':
+		if slices.Contains(layouts, FlexTimeUnixSeconds) {
+			sec, nsec, err := parseUnixSeconds(tok.String())
+			if err != nil {
+				return fmt.Errorf("FlexTime: %s is out of range", tok.String())
+			}
+			t.Time = time.Unix(sec, nsec).UTC()
+			return nil
+		}
+	default:
+		return fmt.Errorf("FlexTime: cannot unmarshal JSON %s", tok.Kind())
+	}
+	return fmt.Errorf("FlexTime: %s matches none of layouts %q", in, layouts)
+}
+
+// parseUnixSeconds parses number literal num into seconds and nanoseconds, truncating digits below nanoseconds.
+// Digits are parsed as they are rather than through float64, which can not hold nanoseconds of the current time.
+func parseUnixSeconds(num string) (sec, nsec int64, err error) {
+	mantissa, exp, hasExp := strings.Cut(strings.ToLower(num), "e")
+	neg := strings.HasPrefix(mantissa, "-")
+	mantissa = strings.TrimPrefix(mantissa, "-")
+	intPart, frac, _ := strings.Cut(mantissa, ".")
+
+	// point is the position of the decimal point in digits.
+	digits, point := intPart+frac, len(intPart)
+	if hasExp {
+		e, err := strconv.Atoi(exp)
+		if err != nil {
+			return 0, 0, err
+		}
+		point += e
+	}
+	switch {
+	case point > len(digits)+19:
+		return 0, 0, strconv.ErrRange
+	case point > len(digits):
+		digits += strings.Repeat("0", point-len(digits))
+	case point < -9:
+		return 0, 0, nil
+	case point < 0:
+		digits, point = strings.Repeat("0", -point)+digits, 0
+	}
+
+	sec, err = strconv.ParseInt("0"+digits[:point], 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	fracDigits := digits[point:]
+	if len(fracDigits) > 9 {
+		fracDigits = fracDigits[:9]
+	}
+	nsec, err = strconv.ParseInt(fracDigits+strings.Repeat("0", 9-len(fracDigits)), 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	if neg {
+		return -sec, -nsec, nil
+	}
+	return sec, nsec, nil
+}
+
+func TestFlexTime(t *testing.T) {
+	type sample struct {
+		At FlexTime `json:"at"`
+	}
+
+	type testCase struct {
+		in       string
+		expected time.Time
+	}
+	for _, tc := range []testCase{
+		{`{"at":"2025-01-02"}`, time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{`{"at":"2025-01-02T03:04:05+09:00"}`, time.Date(2025, 1, 2, 3, 4, 5, 0, time.FixedZone("", 9*60*60))},
+		{`{"at":"2025-01-02T03:04:05.123456789Z"}`, time.Date(2025, 1, 2, 3, 4, 5, 123456789, time.UTC)},
+		{`{"at":1735787045}`, time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{`{"at":1735787045.5}`, time.Date(2025, 1, 2, 3, 4, 5, 5e8, time.UTC)},
+		{`{"at":1735787045.123456789}`, time.Date(2025, 1, 2, 3, 4, 5, 123456789, time.UTC)},
+		{`{"at":1735787045.1234567899}`, time.Date(2025, 1, 2, 3, 4, 5, 123456789, time.UTC)},
+		{`{"at":1.735787045123e9}`, time.Date(2025, 1, 2, 3, 4, 5, 123000000, time.UTC)},
+		{`{"at":1735787045123E-3}`, time.Date(2025, 1, 2, 3, 4, 5, 123000000, time.UTC)},
+		{`{"at":-1.5}`, time.Date(1969, 12, 31, 23, 59, 58, 5e8, time.UTC)},
+		{`{"at":5e-10}`, time.Unix(0, 0)},
+	} {
+		t.Run(tc.in, func(t *testing.T) {
+			var s sample
+			err := json.Unmarshal([]byte(tc.in), &s)
+			if err != nil {
+				panic(err)
+			}
+			if !s.At.Equal(tc.expected) {
+				t.Errorf("not equal: expected(%s) != actual(%s)", tc.expected, s.At)
+			}
+		})
+	}
+
+	bin, err := json.Marshal(sample{FlexTime{time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)}})
+	if err != nil {
+		panic(err)
+	}
+	if expected := `{"at":"2025-01-02T03:04:05Z"}`; string(bin) != expected {
+		t.Errorf("not equal: expected(%s) != actual(%s)", expected, string(bin))
+	}
+
+	var s sample
+	err = json.Unmarshal([]byte(`{"at":"01/02/2025"}`), &s)
+	if err == nil {
+		t.Errorf("should cause an error")
+	}
+	t.Logf("err = %v", err)
+	// flex_time_test.go:173: err = json: cannot unmarshal into Go play.FlexTime within "/at": FlexTime: "01/02/2025" matches none of layouts ["2006-01-02T15:04:05.999999999Z07:00" "2006-01-02T15:04:05Z07:00" "2006-01-02" "unix seconds"]
+
+	err = json.Unmarshal([]byte(`{"at":"01/02/2025"}`), &s, WithFlexTimeLayouts("01/02/2006"))
+	if err != nil {
+		panic(err)
+	}
+	if !s.At.Equal(time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("incorrect: %s", s.At)
+	}
+
+	err = json.Unmarshal([]byte(`{"at":1735787045}`), &s, WithFlexTimeLayouts(time.RFC3339))
+	if err == nil {
+		t.Errorf("should cause an error")
+	}
+	t.Logf("err = %v", err)
+	// flex_time_test.go:188: err = json: cannot unmarshal into Go *play.FlexTime within "/at": FlexTime: 1735787045 matches none of layouts ["2006-01-02T15:04:05Z07:00"]
+
+	for _, in := range []string{`{"at":1e30}`, `{"at":9223372036854775808}`} {
+		err = json.Unmarshal([]byte(in), &s)
+		if err == nil {
+			t.Errorf("%s: should cause an error", in)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package play
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"math"
+	"runtime"
+	"testing"
+)
+
+var ErrInvalidFrame = errors.New("invalid frame")
+
+// NewFramedReader yields JSON values read from r framed as <uint32 big endian length><json bytes>.
+//
+// A zero-length frame carries no value and is skipped, so it can be used as a keep-alive.
+// A frame whose payload is not a single valid JSON value yields an error wrapping ErrInvalidFrame.
+// A frame cut short by the end of r yields io.ErrUnexpectedEOF.
+// The payload buffer grows as data arrives, so a forged length alone can not force a large allocation.
+// Iteration stops after an error is yielded, or at the end of r on a frame boundary.
+func NewFramedReader(r io.Reader) iter.Seq2[jsontext.Value, error] {
+	return func(yield func(jsontext.Value, error) bool) {
+		var header [4]byte
+		for {
+			_, err := io.ReadFull(r, header[:])
+			if err != nil {
+				if !errors.Is(err, io.EOF) {
+					yield(nil, err)
+				}
+				return
+			}
+			n := binary.BigEndian.Uint32(header[:])
+			if n == 0 {
+				continue
+			}
+			// grow as data arrives rather than trusting n for allocation.
+			var buf bytes.Buffer
+			copied, err := io.CopyN(&buf, r, int64(n))
+			if err != nil || copied != int64(n) {
+				if err == nil || errors.Is(err, io.EOF) {
+					err = io.ErrUnexpectedEOF
+				}
+				yield(nil, err)
+				return
+			}
+			val := jsontext.Value(buf.Bytes())
+			if !val.IsValid() {
+				yield(nil, fmt.Errorf("%w: payload of %d bytes is not a valid JSON value", ErrInvalidFrame, n))
+				return
+			}
+			if !yield(val, nil) {
+				return
+			}
+		}
+	}
+}
+
+// FramedWriter writes JSON values to the underlying writer framed as <uint32 big endian length><json bytes>,
+// the counterpart of NewFramedReader.
+type FramedWriter struct {
+	w io.Writer
+}
+
+func NewFramedWriter(w io.Writer) *FramedWriter {
+	return &FramedWriter{w: w}
+}
+
+// WriteValue writes val as a frame. val must be a valid JSON value.
+func (w *FramedWriter) WriteValue(val jsontext.Value) error {
+	if !val.IsValid() {
+		return fmt.Errorf("%w: not a valid JSON value", ErrInvalidFrame)
+	}
+	if uint64(len(val)) > math.MaxUint32 {
+		return fmt.Errorf("%w: %d bytes exceeds max frame size", ErrInvalidFrame, len(val))
+	}
+	frame := binary.BigEndian.AppendUint32(make([]byte, 0, 4+len(val)), uint32(len(val)))
+	_, err := w.w.Write(append(frame, val...))
+	return err
+}
+
+// Encode marshals v and writes it as a frame.
+func (w *FramedWriter) Encode(v any, opts ...json.Options) error {
+	bin, err := json.Marshal(v, opts...)
+	if err != nil {
+		return err
+	}
+	return w.WriteValue(bin)
+}
+
+func TestFramed(t *testing.T) {
+	type message struct {
+		ID     int    `json:"id"`
+		Method string `json:"method"`
+	}
+
+	var buf bytes.Buffer
+	w := NewFramedWriter(&buf)
+	for _, v := range []any{message{1, "ping"}, []int{1, 2, 3}, "foo", nil} {
+		if err := w.Encode(v); err != nil {
+			panic(err)
+		}
+	}
+	if err := w.WriteValue(jsontext.Value(`{"a":`)); !errors.Is(err, ErrInvalidFrame) {
+		t.Errorf("should be ErrInvalidFrame, but is %v", err)
+	}
+	// zero-length frame in the middle.
+	buf.Write([]byte{0, 0, 0, 0})
+	if err := w.WriteValue(jsontext.Value(` {"id": 2, "method": "pong"} `)); err != nil {
+		panic(err)
+	}
+
+	var got []string
+	for val, err := range NewFramedReader(bytes.NewReader(buf.Bytes())) {
+		if err != nil {
+			panic(err)
+		}
+		got = append(got, string(val))
+	}
+	expected := []string{`{"id":1,"method":"ping"}`, `[1,2,3]`, `"foo"`, `null`, ` {"id": 2, "method": "pong"} `}
+	if fmt.Sprint(got) != fmt.Sprint(expected) {
+		t.Errorf("not equal:\nexpected(%q)\n!=\nactual(%q)", expected, got)
+	}
+
+	var m message
+	for val, err := range NewFramedReader(bytes.NewReader(buf.Bytes())) {
+		if err != nil {
+			panic(err)
+		}
+		if err := json.Unmarshal(val, &m); err != nil {
+			panic(err)
+		}
+		break
+	}
+	if m != (message{1, "ping"}) {
+		t.Errorf("incorrect: %#v", m)
+	}
+
+	type testCase struct {
+		name     string
+		in       []byte
+		expected error
+	}
+	for _, tc := range []testCase{
+		{"partial header", buf.Bytes()[:2], io.ErrUnexpectedEOF},
+		{"partial payload", buf.Bytes()[:10], io.ErrUnexpectedEOF},
+		{"header only", buf.Bytes()[:4], io.ErrUnexpectedEOF},
+		{"invalid payload", []byte{0, 0, 0, 2, '{', '"'}, ErrInvalidFrame},
+		{"max length without payload", []byte{0xff, 0xff, 0xff, 0xff}, io.ErrUnexpectedEOF},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				n   int
+				err error
+			)
+			for _, err = range NewFramedReader(bytes.NewReader(tc.in)) {
+				n++
+			}
+			if n != 1 || !errors.Is(err, tc.expected) {
+				t.Errorf("should yield once with %v, but yielded %d times with %v", tc.expected, n, err)
+			}
+		})
+	}
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	for range NewFramedReader(bytes.NewReader([]byte{0xff, 0xff, 0xff, 0xff, '1'})) {
+	}
+	runtime.ReadMemStats(&after)
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 1<<20 {
+		t.Errorf("should not allocate for the declared length, but allocated %d bytes", allocated)
+	}
+}
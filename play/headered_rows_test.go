@@ -0,0 +1,173 @@
+package play
+
+import (
+	"bytes"
+	"encoding/json/jsontext"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+var ErrRowLength = errors.New("row length mismatch")
+
+// HeaderedRowsToObjects reads an array of arrays from dec, where the first element is a header of strings
+// and the rest are rows of values, and writes to enc an array of objects whose names are the header.
+// A row whose length differs from the header is an error wrapping ErrRowLength.
+func HeaderedRowsToObjects(dec *jsontext.Decoder, enc *jsontext.Encoder) error {
+	return headeredRowsToObjects(dec, enc, false)
+}
+
+// HeaderedRowsToObjectsLenient is like HeaderedRowsToObjects
+// but pads short rows with null and drops values exceeding the header.
+func HeaderedRowsToObjectsLenient(dec *jsontext.Decoder, enc *jsontext.Encoder) error {
+	return headeredRowsToObjects(dec, enc, true)
+}
+
+func headeredRowsToObjects(dec *jsontext.Decoder, enc *jsontext.Encoder, lenient bool) error {
+	if err := readBegin(dec, '['); err != nil {
+		return err
+	}
+	if err := enc.WriteToken(jsontext.BeginArray); err != nil {
+		return err
+	}
+	if dec.PeekKind() == ']' {
+		if _, err := dec.ReadToken(); err != nil {
+			return err
+		}
+		return enc.WriteToken(jsontext.EndArray)
+	}
+
+	if err := readBegin(dec, '['); err != nil {
+		return fmt.Errorf("header: %w", err)
+	}
+	var header []string
+	for dec.PeekKind() != ']' {
+		tok, err := dec.ReadToken()
+		if err != nil {
+			return err
+		}
+		if tok.Kind() != '"' {
+			return fmt.Errorf("header: expected string but is %s", tok.Kind())
+		}
+		header = append(header, tok.String())
+	}
+	if _, err := dec.ReadToken(); err != nil {
+		return err
+	}
+
+	for row := 1; dec.PeekKind() != ']'; row++ {
+		if err := readBegin(dec, '['); err != nil {
+			return fmt.Errorf("row %d: %w", row, err)
+		}
+		if err := enc.WriteToken(jsontext.BeginObject); err != nil {
+			return err
+		}
+		i := 0
+		for ; dec.PeekKind() != ']'; i++ {
+			if i >= len(header) {
+				if !lenient {
+					return fmt.Errorf("row %d: %w: header has %d columns, but row has more", row, ErrRowLength, len(header))
+				}
+				if err := dec.SkipValue(); err != nil {
+					return err
+				}
+				continue
+			}
+			val, err := dec.ReadValue()
+			if err != nil {
+				return err
+			}
+			if err := enc.WriteToken(jsontext.String(header[i])); err != nil {
+				return err
+			}
+			if err := enc.WriteValue(val); err != nil {
+				return err
+			}
+		}
+		if i < len(header) {
+			if !lenient {
+				return fmt.Errorf("row %d: %w: header has %d columns, but row has %d", row, ErrRowLength, len(header), i)
+			}
+			for ; i < len(header); i++ {
+				if err := enc.WriteToken(jsontext.String(header[i])); err != nil {
+					return err
+				}
+				if err := enc.WriteToken(jsontext.Null); err != nil {
+					return err
+				}
+			}
+		}
+		if _, err := dec.ReadToken(); err != nil {
+			return err
+		}
+		if err := enc.WriteToken(jsontext.EndObject); err != nil {
+			return err
+		}
+	}
+	if _, err := dec.ReadToken(); err != nil {
+		return err
+	}
+	return enc.WriteToken(jsontext.EndArray)
+}
+
+// readBegin reads '{' or '[' from dec, or returns an error if the next token is not kind.
+func readBegin(dec *jsontext.Decoder, kind jsontext.Kind) error {
+	tok, err := dec.ReadToken()
+	if err != nil {
+		return err
+	}
+	if tok.Kind() != kind {
+		return fmt.Errorf("expected %s but is %s", kind, tok.Kind())
+	}
+	return nil
+}
+
+func TestHeaderedRowsToObjects(t *testing.T) {
+	type testCase struct {
+		in       string
+		lenient  bool
+		expected string
+		err      error
+	}
+	for _, tc := range []testCase{
+		{
+			`[["id","name"],[1,"alice"],[2,{"first":"bob"}]]`,
+			false,
+			`[{"id":1,"name":"alice"},{"id":2,"name":{"first":"bob"}}]`,
+			nil,
+		},
+		{`[]`, false, `[]`, nil},
+		{`[["id"]]`, false, `[]`, nil},
+		{`[["id","name"],[1]]`, false, "", ErrRowLength},
+		{`[["id","name"],[1,"alice",true]]`, false, "", ErrRowLength},
+		{
+			`[["id","name"],[1],[2,"bob",true]]`,
+			true,
+			`[{"id":1,"name":null},{"id":2,"name":"bob"}]`,
+			nil,
+		},
+	} {
+		t.Run(tc.in, func(t *testing.T) {
+			convert := HeaderedRowsToObjects
+			if tc.lenient {
+				convert = HeaderedRowsToObjectsLenient
+			}
+			var buf bytes.Buffer
+			err := convert(jsontext.NewDecoder(strings.NewReader(tc.in)), jsontext.NewEncoder(&buf))
+			if tc.err != nil {
+				if !errors.Is(err, tc.err) {
+					t.Errorf("should be %v, but is %v", tc.err, err)
+				}
+				t.Logf("err = %v", err)
+				return
+			}
+			if err != nil {
+				panic(err)
+			}
+			if got := strings.TrimSpace(buf.String()); got != tc.expected {
+				t.Errorf("not equal: expected(%s) != actual(%s)", tc.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package play
+
+import (
+	"bytes"
+	"encoding/json/jsontext"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+var ErrDuplicateKey = errors.New("duplicate key")
+
+// IndexByField reads an array from dec and writes an object to enc
+// whose names are values at keyPointer in each element and whose values are the elements.
+// The value at keyPointer must be a string or a number.
+//
+// Each element is buffered while its key is looked up, but the array as a whole is streamed.
+// Since the output is streamed, a duplicate key is an error wrapping ErrDuplicateKey rather than last-wins,
+// and a missing key is an error wrapping ErrNotFound.
+func IndexByField(dec *jsontext.Decoder, enc *jsontext.Encoder, keyPointer jsontext.Pointer) error {
+	tok, err := dec.ReadToken()
+	if err != nil {
+		return err
+	}
+	if tok.Kind() != '[' {
+		return fmt.Errorf("expected array but is %s", tok.Kind())
+	}
+	if err := enc.WriteToken(jsontext.BeginObject); err != nil {
+		return err
+	}
+	seen := make(map[string]bool)
+	for i := 0; dec.PeekKind() != ']'; i++ {
+		val, err := dec.ReadValue()
+		if err != nil {
+			return err
+		}
+		var key jsontext.Token
+		err = ReadJSONAt(jsontext.NewDecoder(bytes.NewReader(val)), keyPointer, func(dec *jsontext.Decoder) error {
+			key, err = dec.ReadToken()
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("element %d: key %q: %w", i, keyPointer, err)
+		}
+		if k := key.Kind(); k != '"' && k != '0' {
+			return fmt.Errorf("element %d: key %q: expected string or number but is %s", i, keyPointer, k)
+		}
+		name := key.String()
+		if seen[name] {
+			return fmt.Errorf("element %d: %w: %q", i, ErrDuplicateKey, name)
+		}
+		seen[name] = true
+		if err := enc.WriteToken(jsontext.String(name)); err != nil {
+			return err
+		}
+		if err := enc.WriteValue(val); err != nil {
+			return err
+		}
+	}
+	if _, err := dec.ReadToken(); err != nil {
+		return err
+	}
+	return enc.WriteToken(jsontext.EndObject)
+}
+
+func TestIndexByField(t *testing.T) {
+	type testCase struct {
+		in       string
+		pointer  jsontext.Pointer
+		expected string
+		err      error
+	}
+	for _, tc := range []testCase{
+		{
+			`[{"id":"a","name":"alice"},{"name":"bob","id":"b"}]`,
+			"/id",
+			`{"a":{"id":"a","name":"alice"},"b":{"name":"bob","id":"b"}}`,
+			nil,
+		},
+		{
+			`[{"meta":{"id":1},"v":true},{"meta":{"id":2.50}}]`,
+			"/meta/id",
+			`{"1":{"meta":{"id":1},"v":true},"2.50":{"meta":{"id":2.50}}}`,
+			nil,
+		},
+		{`[]`, "/id", `{}`, nil},
+		{`[{"id":"a"},{"id":"a"}]`, "/id", "", ErrDuplicateKey},
+		{`[{"id":"a"},{"name":"b"}]`, "/id", "", ErrNotFound},
+	} {
+		t.Run(tc.in, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := IndexByField(jsontext.NewDecoder(strings.NewReader(tc.in)), jsontext.NewEncoder(&buf), tc.pointer)
+			if tc.err != nil {
+				if !errors.Is(err, tc.err) {
+					t.Errorf("should be %v, but is %v", tc.err, err)
+				}
+				t.Logf("err = %v", err)
+				return
+			}
+			if err != nil {
+				panic(err)
+			}
+			if got := strings.TrimSpace(buf.String()); got != tc.expected {
+				t.Errorf("not equal: expected(%s) != actual(%s)", tc.expected, got)
+			}
+		})
+	}
+}
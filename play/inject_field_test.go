@@ -0,0 +1,146 @@
+package play
+
+import (
+	"bytes"
+	"encoding/json/jsontext"
+	"iter"
+	"strings"
+	"testing"
+)
+
+// matchPointer reports whether p matches pattern, where a "*" token in pattern matches any single token.
+func matchPointer(pattern, p jsontext.Pointer) bool {
+	next, stop := iter.Pull(p.Tokens())
+	defer stop()
+	for pt := range pattern.Tokens() {
+		t, ok := next()
+		if !ok || (pt != "*" && pt != t) {
+			return false
+		}
+	}
+	_, ok := next()
+	return !ok
+}
+
+type injectFrame struct {
+	depth int
+	ptr   jsontext.Pointer
+	seen  bool
+}
+
+// InjectField reads a value from dec and writes it to enc
+// adding a member name to every object whose pointer matches pointer, where "*" matches any token.
+// The member is appended to the end of the object, and its value is value(ptr) where ptr points to the object.
+// An object already having name is left as is.
+func InjectField(dec *jsontext.Decoder, enc *jsontext.Encoder, pointer jsontext.Pointer, name string, value func(ptr jsontext.Pointer) jsontext.Value) error {
+	return injectField(dec, enc, pointer, name, value, false)
+}
+
+// InjectFieldOverwrite is like InjectField but replaces the value of an existing member name in place.
+func InjectFieldOverwrite(dec *jsontext.Decoder, enc *jsontext.Encoder, pointer jsontext.Pointer, name string, value func(ptr jsontext.Pointer) jsontext.Value) error {
+	return injectField(dec, enc, pointer, name, value, true)
+}
+
+func injectField(dec *jsontext.Decoder, enc *jsontext.Encoder, pointer jsontext.Pointer, name string, value func(ptr jsontext.Pointer) jsontext.Value, overwrite bool) error {
+	var frames []*injectFrame
+	writeMember := func(f *injectFrame) error {
+		if err := enc.WriteToken(jsontext.String(name)); err != nil {
+			return err
+		}
+		return enc.WriteValue(value(f.ptr))
+	}
+
+	depth := dec.StackDepth()
+	for {
+		tok, err := dec.ReadToken()
+		if err != nil {
+			return err
+		}
+		var top *injectFrame
+		if len(frames) > 0 {
+			top = frames[len(frames)-1]
+		}
+
+		switch {
+		case tok.Kind() == '{':
+			if matchPointer(pointer, dec.StackPointer()) {
+				frames = append(frames, &injectFrame{depth: dec.StackDepth(), ptr: dec.StackPointer()})
+			}
+		case tok.Kind() == '}' && top != nil && top.depth == dec.StackDepth()+1:
+			frames = frames[:len(frames)-1]
+			if !top.seen {
+				if err := writeMember(top); err != nil {
+					return err
+				}
+			}
+		case tok.Kind() == '"' && top != nil && top.depth == dec.StackDepth() && justReadName(dec) && tok.String() == name:
+			top.seen = true
+			if overwrite {
+				if err := dec.SkipValue(); err != nil {
+					return err
+				}
+				if err := writeMember(top); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		if err := enc.WriteToken(tok); err != nil {
+			return err
+		}
+		if dec.StackDepth() == depth {
+			return nil
+		}
+	}
+}
+
+func TestInjectField(t *testing.T) {
+	ts := func(ptr jsontext.Pointer) jsontext.Value {
+		return jsontext.Value(`"` + string(ptr) + `@2026-01-01"`)
+	}
+
+	type testCase struct {
+		in        string
+		pointer   jsontext.Pointer
+		overwrite bool
+		expected  string
+	}
+	for _, tc := range []testCase{
+		{
+			`[{"id":1},{"id":2,"nested":{"id":3}},{}]`,
+			"/*",
+			false,
+			`[{"id":1,"_ts":"/0@2026-01-01"},{"id":2,"nested":{"id":3},"_ts":"/1@2026-01-01"},{"_ts":"/2@2026-01-01"}]`,
+		},
+		{
+			`{"items":[{"id":1,"_ts":"old"},{"id":2}],"meta":{}}`,
+			"/items/*",
+			false,
+			`{"items":[{"id":1,"_ts":"old"},{"id":2,"_ts":"/items/1@2026-01-01"}],"meta":{}}`,
+		},
+		{
+			`{"items":[{"id":1,"_ts":{"old":true},"x":1},{"id":2}],"meta":{}}`,
+			"/items/*",
+			true,
+			`{"items":[{"id":1,"_ts":"/items/0@2026-01-01","x":1},{"id":2,"_ts":"/items/1@2026-01-01"}],"meta":{}}`,
+		},
+		{`{"id":1}`, "", false, `{"id":1,"_ts":"@2026-01-01"}`},
+		{`[1,"foo",{"a":{}}]`, "/*/a", false, `[1,"foo",{"a":{"_ts":"/2/a@2026-01-01"}}]`},
+	} {
+		t.Run(tc.in, func(t *testing.T) {
+			inject := InjectField
+			if tc.overwrite {
+				inject = InjectFieldOverwrite
+			}
+			var buf bytes.Buffer
+			err := inject(jsontext.NewDecoder(strings.NewReader(tc.in)), jsontext.NewEncoder(&buf), tc.pointer, "_ts", ts)
+			if err != nil {
+				panic(err)
+			}
+			if got := strings.TrimSpace(buf.String()); got != tc.expected {
+				t.Errorf("not equal: expected(%s) != actual(%s)", tc.expected, got)
+			}
+		})
+	}
+}
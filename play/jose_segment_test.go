@@ -0,0 +1,76 @@
+package play
+
+import (
+	"encoding/base64"
+	"encoding/json/v2"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// DecodeJOSESegment decodes a base64url encoded segment of JWS/JWT, e.g. a header or a payload, and unmarshals it into T.
+// The segment may or may not be padded.
+func DecodeJOSESegment[T any](segment string) (T, error) {
+	var v T
+	bin, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(segment, "="))
+	if err != nil {
+		return v, fmt.Errorf("decode JOSE segment: %w", err)
+	}
+	err = json.Unmarshal(bin, &v)
+	return v, err
+}
+
+func TestDecodeJOSESegment(t *testing.T) {
+	type header struct {
+		Alg string `json:"alg"`
+		Typ string `json:"typ"`
+	}
+	type claims struct {
+		Sub  string `json:"sub"`
+		Name string `json:"name"`
+		Iat  int64  `json:"iat"`
+	}
+
+	// from https://jwt.io
+	const token = "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9." +
+		"eyJzdWIiOiIxMjM0NTY3ODkwIiwibmFtZSI6IkpvaG4gRG9lIiwiaWF0IjoxNTE2MjM5MDIyfQ." +
+		"SflKxwRJSMeKKF2QT4fwpMeJf36POk6yJV_adQssw5c"
+	segments := strings.Split(token, ".")
+
+	h, err := DecodeJOSESegment[header](segments[0])
+	if err != nil {
+		panic(err)
+	}
+	if h != (header{"HS256", "JWT"}) {
+		t.Errorf("incorrect: %#v", h)
+	}
+
+	c, err := DecodeJOSESegment[claims](segments[1])
+	if err != nil {
+		panic(err)
+	}
+	if c != (claims{"1234567890", "John Doe", 1516239022}) {
+		t.Errorf("incorrect: %#v", c)
+	}
+
+	// padded
+	padded := base64.URLEncoding.EncodeToString([]byte(`{"sub":"a"}`))
+	if !strings.HasSuffix(padded, "=") {
+		t.Fatalf("test input should be padded: %s", padded)
+	}
+	c, err = DecodeJOSESegment[claims](padded)
+	if err != nil {
+		panic(err)
+	}
+	if c.Sub != "a" {
+		t.Errorf("incorrect: %#v", c)
+	}
+
+	for _, in := range []string{"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9+", "e", base64.RawURLEncoding.EncodeToString([]byte(`{"sub":`))} {
+		_, err = DecodeJOSESegment[claims](in)
+		if err == nil {
+			t.Errorf("should cause an error: %s", in)
+		}
+		t.Logf("err = %v", err)
+	}
+}
@@ -0,0 +1,84 @@
+package play
+
+import (
+	"encoding/json/jsontext"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"testing"
+)
+
+var ErrKeyPattern = errors.New("key does not match pattern")
+
+// WithKeyPattern rejects an object member name not matching re with an error wrapping ErrKeyPattern,
+// which reports the name and the pointer of the member.
+// Only names in objects at pointers are checked, or names in every object if pointers is empty.
+// A "*" token in pointers matches any token.
+func WithKeyPattern(re *regexp.Regexp, pointers ...jsontext.Pointer) DecodeOption {
+	return func(c *decodeConfig) {
+		c.hooks = append(c.hooks, func(dec *jsontext.Decoder, tok jsontext.Token, emit func(jsontext.Token) error) error {
+			if tok.Kind() != '"' || !justReadName(dec) {
+				return emit(tok)
+			}
+			ptr := dec.StackPointer()
+			if len(pointers) > 0 && !slices.ContainsFunc(pointers, func(p jsontext.Pointer) bool { return matchPointer(p, ptr.Parent()) }) {
+				return emit(tok)
+			}
+			if name := tok.String(); !re.MatchString(name) {
+				return fmt.Errorf("%w: key = %q, pattern = %q, pointer = %q", ErrKeyPattern, name, re, ptr)
+			}
+			return emit(tok)
+		})
+	}
+}
+
+func TestDecodeOption_KeyPattern(t *testing.T) {
+	camel := regexp.MustCompile(`^[a-z][a-zA-Z0-9]*$`)
+
+	type testCase struct {
+		name     string
+		in       string
+		opt      DecodeOption
+		expected string
+	}
+	for _, tc := range []testCase{
+		{"ok", `{"userId":1,"labels":{"any key":"v"}}`, WithKeyPattern(camel, ""), ""},
+		{"ok nested", `{"items":[{"itemId":1},{"itemName":"a"}]}`, WithKeyPattern(camel), ""},
+		{
+			"top-level",
+			`{"userId":1,"user$where":2}`,
+			WithKeyPattern(camel, ""),
+			`key does not match pattern: key = "user$where", pattern = "^[a-z][a-zA-Z0-9]*$", pointer = "/user$where"`,
+		},
+		{
+			"everywhere",
+			`{"labels":{"a/b":"v"}}`,
+			WithKeyPattern(camel),
+			`key does not match pattern: key = "a/b", pattern = "^[a-z][a-zA-Z0-9]*$", pointer = "/labels/a~1b"`,
+		},
+		{
+			"wildcard",
+			`{"items":[{"itemId":1},{"item-name":"a"}]}`,
+			WithKeyPattern(camel, "/items/*"),
+			`key does not match pattern: key = "item-name", pattern = "^[a-z][a-zA-Z0-9]*$", pointer = "/items/1/item-name"`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var v any
+			err := UnmarshalWith([]byte(tc.in), &v, tc.opt)
+			if tc.expected == "" {
+				if err != nil {
+					panic(err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrKeyPattern) {
+				t.Fatalf("should be ErrKeyPattern, but is %v", err)
+			}
+			if err.Error() != tc.expected {
+				t.Errorf("not equal: expected(%s) != actual(%s)", tc.expected, err.Error())
+			}
+		})
+	}
+}
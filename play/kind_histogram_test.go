@@ -0,0 +1,58 @@
+package play
+
+import (
+	"encoding/json/jsontext"
+	"maps"
+	"strings"
+	"testing"
+)
+
+// KindHistogram reads a value from dec and counts values of each kind in it, including the value itself.
+// An object or array is counted once by its '{' or '['.
+// Object names are not counted as strings.
+func KindHistogram(dec *jsontext.Decoder) (map[jsontext.Kind]int, error) {
+	hist := make(map[jsontext.Kind]int)
+	depth := dec.StackDepth()
+	for {
+		tok, err := dec.ReadToken()
+		if err != nil {
+			return hist, err
+		}
+		switch k := tok.Kind(); {
+		case k == '}' || k == ']':
+		case k == '"' && justReadName(dec):
+		default:
+			hist[k]++
+		}
+		if dec.StackDepth() == depth {
+			return hist, nil
+		}
+	}
+}
+
+func TestKindHistogram(t *testing.T) {
+	type testCase struct {
+		in       string
+		expected map[jsontext.Kind]int
+	}
+	for _, tc := range []testCase{
+		{decoderTestInput, map[jsontext.Kind]int{'{': 2, '[': 2, '"': 2, '0': 1, 'n': 2}},
+		{`[true,false,true,{"a":"b"},[]]`, map[jsontext.Kind]int{'[': 2, 't': 2, 'f': 1, '{': 1, '"': 1}},
+		{`1`, map[jsontext.Kind]int{'0': 1}},
+	} {
+		t.Run(tc.in, func(t *testing.T) {
+			hist, err := KindHistogram(jsontext.NewDecoder(strings.NewReader(tc.in)))
+			if err != nil {
+				panic(err)
+			}
+			if !maps.Equal(hist, tc.expected) {
+				t.Errorf("not equal: expected(%v) != actual(%v)", tc.expected, hist)
+			}
+		})
+	}
+
+	_, err := KindHistogram(jsontext.NewDecoder(strings.NewReader(`[1,`)))
+	if err == nil {
+		t.Errorf("should cause an error")
+	}
+}
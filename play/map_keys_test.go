@@ -0,0 +1,129 @@
+package play
+
+import (
+	"bytes"
+	"encoding/json/jsontext"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// MapKeys reads an object from dec and writes it to enc renaming each top-level member name by fn.
+// A member is dropped if fn returns false.
+// Two members renamed to the same name are an error wrapping ErrDuplicateKey.
+func MapKeys(dec *jsontext.Decoder, enc *jsontext.Encoder, fn func(name string) (string, bool)) error {
+	return mapKeys(dec, enc, fn, false)
+}
+
+// MapKeysKeepLast is like MapKeys but keeps the value of the last member on collision, at the position of the first one.
+// Since a later member may replace an earlier one, the whole object is buffered before being written.
+func MapKeysKeepLast(dec *jsontext.Decoder, enc *jsontext.Encoder, fn func(name string) (string, bool)) error {
+	return mapKeys(dec, enc, fn, true)
+}
+
+func mapKeys(dec *jsontext.Decoder, enc *jsontext.Encoder, fn func(name string) (string, bool), keepLast bool) error {
+	if err := readBegin(dec, '{'); err != nil {
+		return err
+	}
+	if err := enc.WriteToken(jsontext.BeginObject); err != nil {
+		return err
+	}
+	var (
+		seen     = make(map[string]bool)
+		buffered OrderedMap[string, jsontext.Value]
+	)
+	for dec.PeekKind() != '}' {
+		tok, err := dec.ReadToken()
+		if err != nil {
+			return err
+		}
+		name, ok := fn(tok.String())
+		if !ok {
+			if err := dec.SkipValue(); err != nil {
+				return err
+			}
+			continue
+		}
+		val, err := dec.ReadValue()
+		if err != nil {
+			return err
+		}
+		if keepLast {
+			buffered.Set(name, val.Clone())
+			continue
+		}
+		if seen[name] {
+			return fmt.Errorf("%w: %q", ErrDuplicateKey, name)
+		}
+		seen[name] = true
+		if err := enc.WriteToken(jsontext.String(name)); err != nil {
+			return err
+		}
+		if err := enc.WriteValue(val); err != nil {
+			return err
+		}
+	}
+	if _, err := dec.ReadToken(); err != nil {
+		return err
+	}
+	for name, val := range buffered.All() {
+		if err := enc.WriteToken(jsontext.String(name)); err != nil {
+			return err
+		}
+		if err := enc.WriteValue(val); err != nil {
+			return err
+		}
+	}
+	return enc.WriteToken(jsontext.EndObject)
+}
+
+func TestMapKeys(t *testing.T) {
+	// snake_case to camelCase, dropping private members prefixed with "_".
+	camel := func(name string) (string, bool) {
+		if strings.HasPrefix(name, "_") {
+			return "", false
+		}
+		parts := strings.Split(name, "_")
+		for i := 1; i < len(parts); i++ {
+			if parts[i] != "" {
+				parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+			}
+		}
+		return strings.Join(parts, ""), true
+	}
+
+	type testCase struct {
+		in       string
+		keepLast bool
+		expected string
+		err      error
+	}
+	for _, tc := range []testCase{
+		{`{"user_id":1,"_secret":"x","display_name":"foo","nested_obj":{"inner_key":1}}`, false, `{"userId":1,"displayName":"foo","nestedObj":{"inner_key":1}}`, nil},
+		{`{}`, false, `{}`, nil},
+		{`{"user_id":1,"userId":2,"name":"foo"}`, false, "", ErrDuplicateKey},
+		{`{"user_id":1,"name":"foo","userId":2,"_x":3}`, true, `{"userId":2,"name":"foo"}`, nil},
+	} {
+		t.Run(tc.in, func(t *testing.T) {
+			rekey := MapKeys
+			if tc.keepLast {
+				rekey = MapKeysKeepLast
+			}
+			var buf bytes.Buffer
+			err := rekey(jsontext.NewDecoder(strings.NewReader(tc.in)), jsontext.NewEncoder(&buf), camel)
+			if tc.err != nil {
+				if !errors.Is(err, tc.err) {
+					t.Errorf("should be %v, but is %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				panic(err)
+			}
+			if got := strings.TrimSpace(buf.String()); got != tc.expected {
+				t.Errorf("not equal: expected(%s) != actual(%s)", tc.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package play
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"errors"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+)
+
+type mapEntry[K, V any] struct {
+	Key   K `json:"key"`
+	Value V `json:"value"`
+}
+
+// MapToNDJSON writes m to w as NDJSON, one {"key":k,"value":v} line per entry in sorted key order,
+// e.g. for a deterministic and diffable dump of m. opts are used for both encoding and marshaling.
+func MapToNDJSON[K cmp.Ordered, V any](w io.Writer, m map[K]V, opts ...json.Options) error {
+	enc := jsontext.NewEncoder(w, opts...)
+	for _, k := range slices.Sorted(maps.Keys(m)) {
+		// the encoder writes a newline after each top-level value.
+		if err := json.MarshalEncode(enc, mapEntry[K, V]{Key: k, Value: m[k]}, opts...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestMapToNDJSON(t *testing.T) {
+	m := map[string][]int{"b": {2}, "c": nil, "a": {1, 1}}
+
+	var buf bytes.Buffer
+	if err := MapToNDJSON(&buf, m); err != nil {
+		panic(err)
+	}
+	expected := `{"key":"a","value":[1,1]}
+{"key":"b","value":[2]}
+{"key":"c","value":[]}
+`
+	if buf.String() != expected {
+		t.Errorf("not equal: expected(%s) != actual(%s)", expected, buf.String())
+	}
+
+	decoded := map[string][]int{}
+	dec := jsontext.NewDecoder(bytes.NewReader(buf.Bytes()))
+	for {
+		var entry mapEntry[string, []int]
+		err := json.UnmarshalDecode(dec, &entry)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			panic(err)
+		}
+		decoded[entry.Key] = entry.Value
+	}
+	m["c"] = []int{} // nil is written as [] by default.
+	if !maps.EqualFunc(m, decoded, slices.Equal) {
+		t.Errorf("not equal: expected(%v) != actual(%v)", m, decoded)
+	}
+
+	var ints strings.Builder
+	if err := MapToNDJSON(&ints, map[int]bool{10: true, -1: false, 2: true}); err != nil {
+		panic(err)
+	}
+	expectedInts := "{\"key\":-1,\"value\":false}\n{\"key\":2,\"value\":true}\n{\"key\":10,\"value\":true}\n"
+	if ints.String() != expectedInts {
+		t.Errorf("not equal: expected(%s) != actual(%s)", expectedInts, ints.String())
+	}
+}
@@ -0,0 +1,142 @@
+package play
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"errors"
+	"maps"
+	"slices"
+	"testing"
+)
+
+// MarshalMapUnd writes m as a JSON object omitting members whose values are undefined.
+//
+// A map value is always written once its key is written,
+// so Und[V].MarshalJSONTo can not omit itself and writes null for undefined.
+// Use this (or WithMapUnd) to keep undefined values absent.
+// Keys are written in sorted order.
+//
+// Option[V] has no absent state: None in a map is written as null as it is elsewhere.
+func MarshalMapUnd[K ~string, V any](enc *jsontext.Encoder, m map[K]Und[V]) error {
+	if m == nil {
+		return enc.WriteToken(jsontext.Null)
+	}
+	if err := enc.WriteToken(jsontext.BeginObject); err != nil {
+		return err
+	}
+	for _, k := range slices.Sorted(maps.Keys(m)) {
+		u := m[k]
+		if u.IsUndefined() {
+			continue
+		}
+		if err := enc.WriteToken(jsontext.String(string(k))); err != nil {
+			return err
+		}
+		if err := json.MarshalEncode(enc, u); err != nil {
+			return err
+		}
+	}
+	return enc.WriteToken(jsontext.EndObject)
+}
+
+// UnmarshalMapUnd reads a JSON object into m.
+// A present member is decoded into null or defined. An absent member simply is not stored in m,
+// and looking it up yields the zero Und, which is undefined.
+func UnmarshalMapUnd[K ~string, V any](dec *jsontext.Decoder, m *map[K]Und[V]) error {
+	if dec.PeekKind() == 'n' {
+		if err := dec.SkipValue(); err != nil {
+			return err
+		}
+		*m = nil
+		return nil
+	}
+	if dec.PeekKind() != '{' {
+		// let the default unmarshaler report the type mismatch.
+		return errors.ErrUnsupported
+	}
+	if _, err := dec.ReadToken(); err != nil {
+		return err
+	}
+	if *m == nil {
+		*m = make(map[K]Und[V])
+	}
+	for dec.PeekKind() != '}' {
+		tok, err := dec.ReadToken()
+		if err != nil {
+			return err
+		}
+		k := K(tok.String())
+		var u Und[V]
+		if err := json.UnmarshalDecode(dec, &u); err != nil {
+			return err
+		}
+		(*m)[k] = u
+	}
+	_, err := dec.ReadToken()
+	return err
+}
+
+// WithMapUnd returns options that marshal and unmarshal map[K]Und[V] by MarshalMapUnd and UnmarshalMapUnd.
+func WithMapUnd[K ~string, V any]() json.Options {
+	return json.JoinOptions(
+		json.WithMarshalers(json.MarshalToFunc(MarshalMapUnd[K, V])),
+		json.WithUnmarshalers(json.UnmarshalFromFunc(UnmarshalMapUnd[K, V])),
+	)
+}
+
+func TestMapUnd(t *testing.T) {
+	m := map[string]Und[int]{
+		"foo": Undefined[int](),
+		"bar": Null[int](),
+		"baz": Defined(0),
+		"qux": Defined(5),
+	}
+
+	bin, err := json.Marshal(m, json.Deterministic(true))
+	if err != nil {
+		panic(err)
+	}
+	t.Logf("without option = %s", bin)
+	// map_und_test.go:99: without option = {"bar":null,"baz":0,"foo":null,"qux":5}
+
+	bin, err = json.Marshal(m, WithMapUnd[string, int](), json.Deterministic(true))
+	if err != nil {
+		panic(err)
+	}
+	expected := `{"bar":null,"baz":0,"qux":5}`
+	if string(bin) != expected {
+		t.Errorf("not equal: expected(%s) != actual(%s)", expected, string(bin))
+	}
+
+	var unmarshaled map[string]Und[int]
+	err = json.Unmarshal(bin, &unmarshaled, WithMapUnd[string, int]())
+	if err != nil {
+		panic(err)
+	}
+	if _, ok := unmarshaled["foo"]; ok {
+		t.Errorf("foo should be absent")
+	}
+	// lookup of absent key yields undefined, same as m["foo"].
+	for k, v := range m {
+		if unmarshaled[k] != v {
+			t.Errorf("not equal at %q: expected(%#v) != actual(%#v)", k, v, unmarshaled[k])
+		}
+	}
+
+	type sample struct {
+		M map[string]Und[int]
+		O map[string]Option[int]
+	}
+	s := sample{
+		M: m,
+		O: map[string]Option[int]{"foo": None[int](), "bar": Some(1)},
+	}
+	bin, err = json.Marshal(s, WithMapUnd[string, int](), json.Deterministic(true))
+	if err != nil {
+		panic(err)
+	}
+	expected = `{"M":{"bar":null,"baz":0,"qux":5},"O":{"bar":1,"foo":null}}`
+	if string(bin) != expected {
+		t.Errorf("not equal: expected(%s) != actual(%s)", expected, string(bin))
+	}
+}
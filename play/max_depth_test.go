@@ -0,0 +1,73 @@
+package play
+
+import (
+	"encoding/json/jsontext"
+	"errors"
+	"strings"
+	"testing"
+)
+
+var ErrMaxDepth = errors.New("max depth exceeded")
+
+// MaxDepth reads a value from dec and returns the deepest nesting of objects and arrays in it.
+// Scalars are depth 0, and [] and {} are depth 1.
+// Depth is counted relative to the stack depth of dec at the call.
+func MaxDepth(dec *jsontext.Decoder) (int, error) {
+	depth := dec.StackDepth()
+	maxDepth := 0
+	for {
+		if _, err := dec.ReadToken(); err != nil {
+			return 0, err
+		}
+		d := dec.StackDepth()
+		maxDepth = max(maxDepth, d-depth)
+		if d == depth {
+			return maxDepth, nil
+		}
+	}
+}
+
+func TestMaxDepth(t *testing.T) {
+	type testCase struct {
+		in       string
+		expected int
+	}
+	for _, tc := range []testCase{
+		{`"foo"`, 0},
+		{`[]`, 1},
+		{`{"foo":[1,2,{"bar":[[]]}],"baz":{}}`, 5},
+		{streamDecodeInput, 4},
+		{strings.Repeat("[", 100) + strings.Repeat("]", 100), 100},
+	} {
+		t.Run(tc.in, func(t *testing.T) {
+			depth, err := MaxDepth(jsontext.NewDecoder(strings.NewReader(tc.in)))
+			if err != nil {
+				panic(err)
+			}
+			if depth != tc.expected {
+				t.Errorf("not equal: expected(%d) != actual(%d)", tc.expected, depth)
+			}
+		})
+	}
+
+	dec := jsontext.NewDecoder(strings.NewReader(`{"foo":[[1]],"bar":[2]}`))
+	for dec.StackPointer() != "/foo" {
+		if _, err := dec.ReadToken(); err != nil {
+			panic(err)
+		}
+	}
+	depth, err := MaxDepth(dec)
+	if err != nil {
+		panic(err)
+	}
+	if depth != 2 {
+		t.Errorf("not equal: expected(%d) != actual(%d)", 2, depth)
+	}
+	tok, err := dec.ReadToken()
+	if err != nil {
+		panic(err)
+	}
+	if tok.String() != "bar" {
+		t.Errorf("should stop right after the value, but next token is %s", tok)
+	}
+}
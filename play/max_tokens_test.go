@@ -0,0 +1,66 @@
+package play
+
+import (
+	"encoding/json/jsontext"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+var ErrTooManyTokens = errors.New("too many tokens")
+
+// WithMaxTokens aborts decoding with ErrTooManyTokens once more than n tokens have been read from the source decoder.
+// Object names, begins and ends of objects and arrays are all counted as tokens.
+// Unlike WithMaxInputBytes, it bounds work for inputs made of many tiny values, e.g. a huge flat array of 0s.
+func WithMaxTokens(n int64) DecodeOption {
+	return func(c *decodeConfig) {
+		var count int64
+		c.hooks = append(c.hooks, func(dec *jsontext.Decoder, tok jsontext.Token, emit func(jsontext.Token) error) error {
+			count++
+			if count > n {
+				return fmt.Errorf("%w: limit = %d, pointer = %q", ErrTooManyTokens, n, dec.StackPointer())
+			}
+			return emit(tok)
+		})
+	}
+}
+
+func TestDecodeOption_MaxTokens(t *testing.T) {
+	type sample struct {
+		Foo string `json:"foo"`
+		Bar []int  `json:"bar"`
+	}
+
+	// {, "foo", "foo", "bar", [, 1000 elements, ] and }
+	input := `{"foo":"foo","bar":[` + strings.Repeat("0,", 999) + "0]}"
+	const tokens = 1007
+
+	var s sample
+	err := UnmarshalWith([]byte(input), &s, WithMaxTokens(tokens))
+	if err != nil {
+		panic(err)
+	}
+	if s.Foo != "foo" || len(s.Bar) != 1000 {
+		t.Errorf("incorrect: %#v", s)
+	}
+
+	s = sample{}
+	err = UnmarshalWith([]byte(input), &s, WithMaxTokens(tokens-1))
+	if !errors.Is(err, ErrTooManyTokens) {
+		t.Fatalf("should be ErrTooManyTokens, but is %v", err)
+	}
+	t.Logf("err = %v", err)
+	// max_tokens_test.go:53: err = too many tokens: limit = 1006, pointer = ""
+
+	s = sample{}
+	err = UnmarshalWith([]byte(input), &s, WithMaxTokens(100))
+	if !errors.Is(err, ErrTooManyTokens) {
+		t.Fatalf("should be ErrTooManyTokens, but is %v", err)
+	}
+	t.Logf("err = %v", err)
+	// max_tokens_test.go:61: err = too many tokens: limit = 100, pointer = "/bar/95"
+	if len(s.Bar) >= 1000 {
+		t.Errorf("should be stopped partway, but decoded %d elements", len(s.Bar))
+	}
+}
@@ -0,0 +1,238 @@
+package play
+
+import (
+	"bytes"
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+// ApplyMergePatch applies patchJSON to target as a JSON Merge Patch (RFC 7386).
+//
+// Each member of the patch is handled as if decoded into an Und field of a shadow struct:
+// an absent member (undefined) leaves the field untouched, null resets it to the zero value,
+// and a value overwrites it. A patch object onto a struct, a map with string keys, or a pointer to them
+// is merged recursively, as is one onto an interface holding such a map; a null member of a map deletes the entry.
+// Any other value, including arrays and types with custom unmarshal methods such as Option and Und, is replaced as a whole.
+// Members with no corresponding field are ignored, as json.Unmarshal does by default.
+func ApplyMergePatch[T any](target *T, patchJSON []byte) error {
+	patch := jsontext.Value(patchJSON)
+	if !patch.IsValid() {
+		return errors.New("invalid merge patch")
+	}
+	return mergePatch(reflect.ValueOf(target).Elem(), patch, "")
+}
+
+func mergePatch(rv reflect.Value, patch jsontext.Value, ptr jsontext.Pointer) error {
+	if patch.Kind() != '{' || hasCustomUnmarshaler(rv.Type()) {
+		return replaceByPatch(rv, patch, ptr)
+	}
+
+	switch rv.Kind() {
+	case reflect.Pointer:
+		if rv.IsNil() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+		return mergePatch(rv.Elem(), patch, ptr)
+	case reflect.Interface:
+		if rv.IsNil() || rv.Elem().Kind() != reflect.Map {
+			return replaceByPatch(rv, patch, ptr)
+		}
+		// rv.Elem() is not settable; merge into a settable copy of the map reference.
+		m := reflect.New(rv.Elem().Type()).Elem()
+		m.Set(rv.Elem())
+		if err := mergePatch(m, patch, ptr); err != nil {
+			return err
+		}
+		rv.Set(m)
+		return nil
+	case reflect.Struct:
+		fields := mergePatchFields(rv.Type())
+		return forEachPatchMember(patch, func(name string, val jsontext.Value) error {
+			index, ok := fields[name]
+			if !ok {
+				return nil
+			}
+			f := rv.FieldByIndex(index)
+			if val.Kind() == 'n' {
+				f.SetZero()
+				return nil
+			}
+			return mergePatch(f, val, ptr.AppendToken(name))
+		})
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return replaceByPatch(rv, patch, ptr)
+		}
+		if rv.IsNil() {
+			rv.Set(reflect.MakeMap(rv.Type()))
+		}
+		return forEachPatchMember(patch, func(name string, val jsontext.Value) error {
+			key := reflect.ValueOf(name).Convert(rv.Type().Key())
+			if val.Kind() == 'n' {
+				rv.SetMapIndex(key, reflect.Value{})
+				return nil
+			}
+			elem := reflect.New(rv.Type().Elem()).Elem()
+			if cur := rv.MapIndex(key); cur.IsValid() {
+				elem.Set(cur)
+			}
+			if err := mergePatch(elem, val, ptr.AppendToken(name)); err != nil {
+				return err
+			}
+			rv.SetMapIndex(key, elem)
+			return nil
+		})
+	}
+	return replaceByPatch(rv, patch, ptr)
+}
+
+func replaceByPatch(rv reflect.Value, patch jsontext.Value, ptr jsontext.Pointer) error {
+	v := reflect.New(rv.Type())
+	if err := json.Unmarshal(patch, v.Interface()); err != nil {
+		return fmt.Errorf("merge patch at %q: %w", ptr, err)
+	}
+	rv.Set(v.Elem())
+	return nil
+}
+
+func hasCustomUnmarshaler(t reflect.Type) bool {
+	return slices.ContainsFunc(customUnmarshalerTypes, func(it reflect.Type) bool {
+		return t.Implements(it) || reflect.PointerTo(t).Implements(it)
+	})
+}
+
+func forEachPatchMember(patch jsontext.Value, fn func(name string, val jsontext.Value) error) error {
+	dec := jsontext.NewDecoder(bytes.NewReader(patch))
+	if err := readBegin(dec, '{'); err != nil {
+		return err
+	}
+	for dec.PeekKind() != '}' {
+		tok, err := dec.ReadToken()
+		if err != nil {
+			return err
+		}
+		name := tok.String()
+		val, err := dec.ReadValue()
+		if err != nil {
+			return err
+		}
+		if err := fn(name, val); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergePatchFields returns index paths of fields of struct type t keyed by their JSON names,
+// including fields of inlined structs. Fields of inlined pointers are not included.
+func mergePatchFields(t reflect.Type) map[string][]int {
+	fields := make(map[string][]int)
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		name, inline, ok := jsonFieldName(sf)
+		if !ok {
+			continue
+		}
+		if !inline {
+			fields[name] = []int{i}
+			continue
+		}
+		if sf.Type.Kind() != reflect.Struct {
+			continue
+		}
+		for name, index := range mergePatchFields(sf.Type) {
+			if _, ok := fields[name]; !ok {
+				fields[name] = append([]int{i}, index...)
+			}
+		}
+	}
+	return fields
+}
+
+func TestApplyMergePatch(t *testing.T) {
+	type Base struct {
+		ID string `json:"id"`
+	}
+	type address struct {
+		City string `json:"city"`
+		Zip  string `json:"zip"`
+	}
+	type user struct {
+		Base
+		Name    string            `json:"name"`
+		Age     int               `json:"age"`
+		Tags    []string          `json:"tags"`
+		Address address           `json:"address"`
+		Backup  *address          `json:"backup"`
+		Labels  map[string]string `json:"labels"`
+		Nick    Option[string]    `json:"nick"`
+		Extra   any               `json:"extra"`
+	}
+
+	newTarget := func() user {
+		return user{
+			Base:    Base{ID: "u1"},
+			Name:    "alice",
+			Age:     30,
+			Tags:    []string{"a", "b"},
+			Address: address{City: "Tokyo", Zip: "100"},
+			Labels:  map[string]string{"team": "x", "role": "dev"},
+			Nick:    Some("al"),
+			Extra:   map[string]any{"k": "v", "n": 1.0},
+		}
+	}
+
+	type testCase struct {
+		name     string
+		patch    string
+		expected func(u *user)
+	}
+	for _, tc := range []testCase{
+		{"empty", `{}`, func(u *user) {}},
+		{
+			"set, null and absent",
+			`{"name":"bob","age":null,"tags":["c"],"unknown":1}`,
+			func(u *user) { u.Name, u.Age, u.Tags = "bob", 0, []string{"c"} },
+		},
+		{
+			"nested",
+			`{"address":{"zip":"200"},"backup":{"city":"Osaka"},"id":"u2"}`,
+			func(u *user) { u.Address.Zip, u.Backup, u.ID = "200", &address{City: "Osaka"}, "u2" },
+		},
+		{
+			"map",
+			`{"labels":{"role":null,"env":"prod"},"extra":{"n":null,"m":[1]}}`,
+			func(u *user) {
+				u.Labels = map[string]string{"team": "x", "env": "prod"}
+				u.Extra = map[string]any{"k": "v", "m": []any{1.0}}
+			},
+		},
+		{"custom unmarshaler", `{"nick":null}`, func(u *user) { u.Nick = None[string]() }},
+		{"reset objects", `{"address":null,"labels":null}`, func(u *user) { u.Address, u.Labels = address{}, nil }},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			target := newTarget()
+			if err := ApplyMergePatch(&target, []byte(tc.patch)); err != nil {
+				panic(err)
+			}
+			expected := newTarget()
+			tc.expected(&expected)
+			if !reflect.DeepEqual(target, expected) {
+				t.Errorf("not equal:\nexpected(%#v)\nactual(%#v)", expected, target)
+			}
+		})
+	}
+
+	target := newTarget()
+	err := ApplyMergePatch(&target, []byte(`{"address":{"zip":1}}`))
+	if err == nil {
+		t.Errorf("should fail for a type mismatch")
+	}
+	t.Logf("err = %v", err)
+	// merge_patch_test.go:236: err = merge patch at "/address/zip": json: cannot unmarshal JSON number into Go string
+}
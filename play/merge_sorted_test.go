@@ -0,0 +1,160 @@
+package play
+
+import (
+	"bytes"
+	"encoding/json/jsontext"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var ErrNotSorted = errors.New("input not sorted")
+
+// MergeSortedArrays merges arrays read from decs, each already sorted by the value at keyPointer in each element,
+// into a single sorted array written to enc.
+// less compares keys. Elements with equal keys are written in the order of decs.
+//
+// Each dec must be positioned at an array. Only one element per input is buffered at a time.
+// An element lacking the key is an error wrapping ErrNotFound,
+// and an input out of order is an error wrapping ErrNotSorted.
+func MergeSortedArrays(
+	enc *jsontext.Encoder,
+	keyPointer jsontext.Pointer,
+	less func(a, b jsontext.Value) bool,
+	decs ...*jsontext.Decoder,
+) error {
+	type head struct {
+		dec      *jsontext.Decoder
+		elem     jsontext.Value
+		key      jsontext.Value
+		index    int
+		finished bool
+	}
+	heads := make([]*head, len(decs))
+	advance := func(i int) error {
+		h := heads[i]
+		if h.dec.PeekKind() == ']' {
+			h.finished = true
+			_, err := h.dec.ReadToken()
+			return err
+		}
+		elem, err := h.dec.ReadValue()
+		if err != nil {
+			return err
+		}
+		elem = elem.Clone()
+		key := elem
+		if keyPointer != "" {
+			err = ReadJSONAt(jsontext.NewDecoder(bytes.NewReader(elem)), keyPointer, func(dec *jsontext.Decoder) error {
+				key, err = dec.ReadValue()
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("input %d: element %d: key %q: %w", i, h.index, keyPointer, err)
+			}
+		}
+		if h.elem != nil && less(key, h.key) {
+			return fmt.Errorf("input %d: element %d: %w: %s after %s", i, h.index, ErrNotSorted, key, h.key)
+		}
+		h.elem, h.key = elem, key
+		h.index++
+		return nil
+	}
+
+	for i, dec := range decs {
+		heads[i] = &head{dec: dec}
+		if err := readBegin(dec, '['); err != nil {
+			return fmt.Errorf("input %d: %w", i, err)
+		}
+		if err := advance(i); err != nil {
+			return err
+		}
+	}
+
+	if err := enc.WriteToken(jsontext.BeginArray); err != nil {
+		return err
+	}
+	for {
+		// Linear scan; the number of inputs is expected to be small.
+		next := -1
+		for i, h := range heads {
+			if !h.finished && (next < 0 || less(h.key, heads[next].key)) {
+				next = i
+			}
+		}
+		if next < 0 {
+			break
+		}
+		if err := enc.WriteValue(heads[next].elem); err != nil {
+			return err
+		}
+		if err := advance(next); err != nil {
+			return err
+		}
+	}
+	return enc.WriteToken(jsontext.EndArray)
+}
+
+func TestMergeSortedArrays(t *testing.T) {
+	byTs := func(a, b jsontext.Value) bool {
+		x, _ := strconv.Atoi(string(a))
+		y, _ := strconv.Atoi(string(b))
+		return x < y
+	}
+
+	type testCase struct {
+		name     string
+		inputs   []string
+		expected string
+	}
+	for _, tc := range []testCase{
+		{
+			"two shards",
+			[]string{
+				`[{"ts":1,"s":"a"},{"ts":4,"s":"a"},{"ts":5,"s":"a"}]`,
+				`[{"ts":2,"s":"b"},{"ts":4,"s":"b"},{"ts":9,"s":"b"}]`,
+			},
+			`[{"ts":1,"s":"a"},{"ts":2,"s":"b"},{"ts":4,"s":"a"},{"ts":4,"s":"b"},{"ts":5,"s":"a"},{"ts":9,"s":"b"}]`,
+		},
+		{
+			"empty and uneven",
+			[]string{`[]`, `[{"ts":3}]`, `[{"ts":1},{"ts":2},{"ts":7}]`},
+			`[{"ts":1},{"ts":2},{"ts":3},{"ts":7}]`,
+		},
+		{"no input", nil, `[]`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var decs []*jsontext.Decoder
+			for _, in := range tc.inputs {
+				decs = append(decs, jsontext.NewDecoder(strings.NewReader(in)))
+			}
+			var buf bytes.Buffer
+			err := MergeSortedArrays(jsontext.NewEncoder(&buf), "/ts", byTs, decs...)
+			if err != nil {
+				panic(err)
+			}
+			if got := strings.TrimSpace(buf.String()); got != tc.expected {
+				t.Errorf("not equal: expected(%s) != actual(%s)", tc.expected, got)
+			}
+		})
+	}
+
+	var buf bytes.Buffer
+	err := MergeSortedArrays(jsontext.NewEncoder(&buf), "", byTs,
+		jsontext.NewDecoder(strings.NewReader(`[1,3,2]`)),
+		jsontext.NewDecoder(strings.NewReader(`[2]`)),
+	)
+	if !errors.Is(err, ErrNotSorted) {
+		t.Errorf("should be ErrNotSorted, but is %v", err)
+	}
+	err = MergeSortedArrays(jsontext.NewEncoder(&bytes.Buffer{}), "/ts", byTs,
+		jsontext.NewDecoder(strings.NewReader(`[{"ts":1},{"id":2}]`)),
+	)
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("should be ErrNotFound, but is %v", err)
+	}
+	t.Logf("err = %v", err)
+	// merge_sorted_test.go:158: err = input 0: element 1: key "/ts": not found
+}
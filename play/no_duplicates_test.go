@@ -0,0 +1,94 @@
+package play
+
+import (
+	"encoding/json/jsontext"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+var ErrDuplicateMember = errors.New("duplicate member")
+
+// DuplicateMemberError is returned by decoding with WithNoDuplicatesReport.
+// errors.Is(err, ErrDuplicateMember) reports true for it.
+type DuplicateMemberError struct {
+	// Pointer points to the second occurrence of the member.
+	Pointer jsontext.Pointer
+	Name    string
+}
+
+func (e *DuplicateMemberError) Error() string {
+	return fmt.Sprintf("%s: name = %q, pointer = %q", ErrDuplicateMember, e.Name, e.Pointer)
+}
+
+func (e *DuplicateMemberError) Is(target error) bool {
+	return target == ErrDuplicateMember
+}
+
+// WithNoDuplicatesReport rejects duplicate object member names with *DuplicateMemberError.
+//
+// The source decoder made by UnmarshalWith or UnmarshalReadWith is set to allow duplicate names
+// so that the hook sees them. A decoder passed to UnmarshalDecodeWith must be made with
+// jsontext.AllowDuplicateNames(true), or it reports duplicates with its own error first.
+func WithNoDuplicatesReport() DecodeOption {
+	return func(c *decodeConfig) {
+		c.decOpts = append(c.decOpts, jsontext.AllowDuplicateNames(true))
+		var seen []map[string]bool
+		c.hooks = append(c.hooks, func(dec *jsontext.Decoder, tok jsontext.Token, emit func(jsontext.Token) error) error {
+			switch tok.Kind() {
+			case '{':
+				seen = append(seen, make(map[string]bool))
+			case '}':
+				seen = seen[:len(seen)-1]
+			case '"':
+				if justReadName(dec) {
+					name := tok.String()
+					scope := seen[len(seen)-1]
+					if scope[name] {
+						return &DuplicateMemberError{Pointer: dec.StackPointer(), Name: name}
+					}
+					scope[name] = true
+				}
+			}
+			return emit(tok)
+		})
+	}
+}
+
+func TestDecodeOption_NoDuplicatesReport(t *testing.T) {
+	type user struct {
+		Name string `json:"name"`
+	}
+	type sample struct {
+		User  user   `json:"user"`
+		Users []user `json:"users"`
+	}
+
+	var s sample
+	err := UnmarshalWith(
+		[]byte(`{"user":{"name":"foo"},"users":[{"name":"bar"},{"name":"baz"}]}`),
+		&s,
+		WithNoDuplicatesReport(),
+	)
+	if err != nil {
+		panic(err)
+	}
+	if s.User.Name != "foo" || len(s.Users) != 2 {
+		t.Errorf("incorrect: %#v", s)
+	}
+
+	s = sample{}
+	err = UnmarshalWith([]byte(`{"user":{"name":"foo","name":"bar"}}`), &s, WithNoDuplicatesReport())
+	if !errors.Is(err, ErrDuplicateMember) {
+		t.Fatalf("should be ErrDuplicateMember, but is %v", err)
+	}
+	var dupErr *DuplicateMemberError
+	if !errors.As(err, &dupErr) {
+		t.Fatalf("should be *DuplicateMemberError, but is %T", err)
+	}
+	if dupErr.Pointer != "/user/name" || dupErr.Name != "name" {
+		t.Errorf("incorrect: %#v", dupErr)
+	}
+	t.Logf("err = %v", err)
+	// no_duplicates_test.go:92: err = duplicate member: name = "name", pointer = "/user/name"
+}
@@ -0,0 +1,88 @@
+package play
+
+import (
+	"encoding/json/jsontext"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+var ErrEmptyArray = errors.New("empty array")
+
+// WithNonEmptyArrays rejects an empty array or null at any of pointers with an error wrapping ErrEmptyArray.
+// A "*" token in pointers matches any token. An absent member is not checked; combine with a required field check for that.
+func WithNonEmptyArrays(pointers ...jsontext.Pointer) DecodeOption {
+	return func(c *decodeConfig) {
+		// pending is true right after a watched array at opened begins.
+		var (
+			opened  jsontext.Pointer
+			pending bool
+		)
+		c.hooks = append(c.hooks, func(dec *jsontext.Decoder, tok jsontext.Token, emit func(jsontext.Token) error) error {
+			if pending {
+				pending = false
+				if tok.Kind() == ']' {
+					return fmt.Errorf("%w: pointer = %q", ErrEmptyArray, opened)
+				}
+			}
+			if k := tok.Kind(); k == '[' || k == 'n' {
+				// StackPointer points to the array itself while it has no element.
+				ptr := dec.StackPointer()
+				if slices.ContainsFunc(pointers, func(p jsontext.Pointer) bool { return matchPointer(p, ptr) }) {
+					if k == 'n' {
+						return fmt.Errorf("%w: null, pointer = %q", ErrEmptyArray, ptr)
+					}
+					opened, pending = ptr, true
+				}
+			}
+			return emit(tok)
+		})
+	}
+}
+
+func TestDecodeOption_NonEmptyArrays(t *testing.T) {
+	type group struct {
+		Members []string `json:"members"`
+	}
+	type sample struct {
+		Items  []int   `json:"items"`
+		Groups []group `json:"groups"`
+	}
+	opts := []DecodeOption{WithNonEmptyArrays("/items", "/groups/*/members")}
+
+	var s sample
+	err := UnmarshalWith([]byte(`{"items":[1],"groups":[{"members":["a"]},{"members":["b","c"]}]}`), &s, opts...)
+	if err != nil {
+		panic(err)
+	}
+	if len(s.Items) != 1 || len(s.Groups) != 2 {
+		t.Errorf("incorrect: %#v", s)
+	}
+	// not listed
+	err = UnmarshalWith([]byte(`{"items":[[]],"groups":[]}`), &struct{ Items [][]int }{}, opts...)
+	if err != nil {
+		panic(err)
+	}
+
+	type testCase struct {
+		in       string
+		expected string
+	}
+	for _, tc := range []testCase{
+		{`{"items":[]}`, `empty array: pointer = "/items"`},
+		{`{"items":null}`, `empty array: null, pointer = "/items"`},
+		{`{"items":[1],"groups":[{"members":["a"]},{"members":[]}]}`, `empty array: pointer = "/groups/1/members"`},
+	} {
+		t.Run(tc.in, func(t *testing.T) {
+			var s sample
+			err := UnmarshalWith([]byte(tc.in), &s, opts...)
+			if !errors.Is(err, ErrEmptyArray) {
+				t.Fatalf("should be ErrEmptyArray, but is %v", err)
+			}
+			if err.Error() != tc.expected {
+				t.Errorf("not equal: expected(%s) != actual(%s)", tc.expected, err.Error())
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package play
+
+import (
+	"bytes"
+	"encoding/json/jsontext"
+	"math/big"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// maxNormalizedExponent limits the exponent NormalizeIntegers expands,
+// since 1e1000000 would otherwise be written as a million digits.
+const maxNormalizedExponent = 1000
+
+// NormalizeIntegers reads a value from dec and writes it to enc
+// rewriting integral numbers in fractional or exponent notation, e.g. 1.0 or 2e2, as plain integers.
+// Numbers are converted exactly without going through float64, so large integers keep their precision.
+// Fractional numbers, and numbers with an exponent larger than 1000 in magnitude, are written as is.
+func NormalizeIntegers(dec *jsontext.Decoder, enc *jsontext.Encoder) error {
+	depth := dec.StackDepth()
+	for {
+		tok, err := dec.ReadToken()
+		if err != nil {
+			return err
+		}
+		if tok.Kind() == '0' {
+			if i, ok := normalizeInteger(tok.String()); ok {
+				err = enc.WriteValue(jsontext.Value(i))
+			} else {
+				err = enc.WriteToken(tok)
+			}
+		} else {
+			err = enc.WriteToken(tok)
+		}
+		if err != nil {
+			return err
+		}
+		if dec.StackDepth() == depth {
+			return nil
+		}
+	}
+}
+
+// normalizeInteger returns num as a plain integer if num is integral and not already plain.
+func normalizeInteger(num string) (string, bool) {
+	mantissa, exp, hasExp := strings.Cut(strings.ToLower(num), "e")
+	if !hasExp && !strings.Contains(mantissa, ".") {
+		return "", false
+	}
+	if hasExp {
+		e, err := strconv.Atoi(exp)
+		if err != nil || e > maxNormalizedExponent || e < -maxNormalizedExponent {
+			return "", false
+		}
+	}
+	var r big.Rat
+	if _, ok := r.SetString(num); !ok || !r.IsInt() {
+		return "", false
+	}
+	return r.Num().String(), true
+}
+
+func TestNormalizeIntegers(t *testing.T) {
+	type testCase struct {
+		in       string
+		expected string
+	}
+	for _, tc := range []testCase{
+		{`{"a":1.0,"b":1.5,"c":2e2}`, `{"a":1,"b":1.5,"c":200}`},
+		{`[2.00,-3.0,0.0,-0.0,1E+2,150e-1,1.25e-1,12345]`, `[2,-3,0,0,100,15,1.25e-1,12345]`},
+		// beyond float64 precision.
+		{`[12345678901234567890123.0,9007199254740993.0]`, `[12345678901234567890123,9007199254740993]`},
+		{`[1e1001,1e-1001,1.0e1000]`, `[1e1001,1e-1001,1` + strings.Repeat("0", 1000) + `]`},
+		{`{"nested":{"list":[1.0,{"x":3.000}]},"s":"1.0"}`, `{"nested":{"list":[1,{"x":3}]},"s":"1.0"}`},
+	} {
+		t.Run(tc.in[:min(len(tc.in), 40)], func(t *testing.T) {
+			var buf bytes.Buffer
+			err := NormalizeIntegers(jsontext.NewDecoder(strings.NewReader(tc.in)), jsontext.NewEncoder(&buf))
+			if err != nil {
+				panic(err)
+			}
+			if got := strings.TrimSpace(buf.String()); got != tc.expected {
+				t.Errorf("not equal: expected(%s) != actual(%s)", tc.expected, got)
+			}
+		})
+	}
+}
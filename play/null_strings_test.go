@@ -0,0 +1,71 @@
+package play
+
+import (
+	"encoding/json/jsontext"
+	"slices"
+	"testing"
+)
+
+// WithNullStrings replaces every string value equal to any of values with null,
+// e.g. "N/A" or "" often found in JSON exported from spreadsheets,
+// so that an Option, Und or pointer target decodes it as none.
+// Object names are kept as is.
+//
+// The replacement is global: a plain string field receiving a matched value is set to its zero value,
+// as json v2 does for null.
+func WithNullStrings(values ...string) DecodeOption {
+	return func(c *decodeConfig) {
+		c.hooks = append(c.hooks, func(dec *jsontext.Decoder, tok jsontext.Token, emit func(jsontext.Token) error) error {
+			if tok.Kind() != '"' || !slices.Contains(values, tok.String()) {
+				return emit(tok)
+			}
+			if justReadName(dec) {
+				return emit(tok)
+			}
+			return emit(jsontext.Null)
+		})
+	}
+}
+
+func TestDecodeOption_NullStrings(t *testing.T) {
+	type sample struct {
+		Owner Option[string] `json:"owner"`
+		Count *int           `json:"count"`
+		Note  Und[string]    `json:"note"`
+		Plain string         `json:"plain"`
+		Tags  []Option[string]
+		Raw   map[string]string
+	}
+
+	input := []byte(`{"owner":"N/A","count":"","note":"null","plain":"N/A","Tags":["a","N/A","b"],"Raw":{"N/A":"x"}}`)
+
+	var s sample
+	err := UnmarshalWith(input, &s, WithNullStrings("N/A", "", "null"))
+	if err != nil {
+		panic(err)
+	}
+	if s.Owner.IsSome() {
+		t.Errorf("owner should be none: %#v", s.Owner)
+	}
+	if s.Count != nil {
+		t.Errorf("count should be nil: %v", *s.Count)
+	}
+	if !s.Note.IsNull() {
+		t.Errorf("note should be null: %#v", s.Note)
+	}
+	if s.Plain != "" {
+		t.Errorf("plain should be zero: %q", s.Plain)
+	}
+	if len(s.Tags) != 3 || s.Tags[0].Value() != "a" || s.Tags[1].IsSome() || s.Tags[2].Value() != "b" {
+		t.Errorf("incorrect: %#v", s.Tags)
+	}
+	if s.Raw["N/A"] != "x" {
+		t.Errorf("names should be kept: %#v", s.Raw)
+	}
+
+	s = sample{}
+	err = UnmarshalWith(input, &s)
+	if err == nil {
+		t.Errorf("should cause an error without the option")
+	}
+}
@@ -0,0 +1,90 @@
+package play
+
+import (
+	"encoding/json/jsontext"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+var ErrOutOfRange = errors.New("number out of range")
+
+// OutOfRangeError is returned by decoding with WithNumberRange.
+// errors.Is(err, ErrOutOfRange) reports true for it.
+type OutOfRangeError struct {
+	Pointer jsontext.Pointer
+	// Value is the number as written in the input.
+	Value    string
+	Min, Max float64
+}
+
+func (e *OutOfRangeError) Error() string {
+	return fmt.Sprintf("%s: value = %s, range = [%g, %g], pointer = %q", ErrOutOfRange, e.Value, e.Min, e.Max, e.Pointer)
+}
+
+func (e *OutOfRangeError) Is(target error) bool {
+	return target == ErrOutOfRange
+}
+
+// WithNumberRange rejects a number outside [min, max] at any of pointers with *OutOfRangeError.
+// A "*" token in pointers matches any token. With no pointers, every number is checked.
+func WithNumberRange(min, max float64, pointers ...jsontext.Pointer) DecodeOption {
+	return func(c *decodeConfig) {
+		c.hooks = append(c.hooks, func(dec *jsontext.Decoder, tok jsontext.Token, emit func(jsontext.Token) error) error {
+			if tok.Kind() != '0' {
+				return emit(tok)
+			}
+			ptr := dec.StackPointer()
+			if len(pointers) > 0 && !slices.ContainsFunc(pointers, func(p jsontext.Pointer) bool { return matchPointer(p, ptr) }) {
+				return emit(tok)
+			}
+			// Float never fails for a syntactically valid number; out of float64 range becomes ±Inf, which is out of range anyway.
+			if f, _ := tok.Float(); f < min || max < f {
+				return &OutOfRangeError{Pointer: ptr, Value: tok.String(), Min: min, Max: max}
+			}
+			return emit(tok)
+		})
+	}
+}
+
+func TestDecodeOption_NumberRange(t *testing.T) {
+	type sample struct {
+		Score  float64 `json:"score"`
+		Counts []int   `json:"counts"`
+	}
+
+	var s sample
+	err := UnmarshalWith([]byte(`{"score":99.5,"counts":[-1,1000]}`), &s, WithNumberRange(0, 100, "/score"))
+	if err != nil {
+		panic(err)
+	}
+	if s.Score != 99.5 || len(s.Counts) != 2 {
+		t.Errorf("incorrect: %#v", s)
+	}
+
+	type testCase struct {
+		in       string
+		opt      DecodeOption
+		expected OutOfRangeError
+	}
+	for _, tc := range []testCase{
+		{`{"score":100.5}`, WithNumberRange(0, 100, "/score"), OutOfRangeError{"/score", "100.5", 0, 100}},
+		{`{"score":-1}`, WithNumberRange(0, 100, "/score"), OutOfRangeError{"/score", "-1", 0, 100}},
+		{`{"score":1,"counts":[1,2,30]}`, WithNumberRange(0, 10), OutOfRangeError{"/counts/2", "30", 0, 10}},
+		{`{"counts":[1,1e400]}`, WithNumberRange(0, 10, "/counts/*"), OutOfRangeError{"/counts/1", "1e400", 0, 10}},
+	} {
+		t.Run(tc.in, func(t *testing.T) {
+			var s sample
+			err := UnmarshalWith([]byte(tc.in), &s, tc.opt)
+			var rangeErr *OutOfRangeError
+			if !errors.Is(err, ErrOutOfRange) || !errors.As(err, &rangeErr) {
+				t.Fatalf("should be *OutOfRangeError, but is %v", err)
+			}
+			if *rangeErr != tc.expected {
+				t.Errorf("not equal: expected(%#v) != actual(%#v)", tc.expected, *rangeErr)
+			}
+			t.Logf("err = %v", err)
+		})
+	}
+}
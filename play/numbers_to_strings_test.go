@@ -0,0 +1,44 @@
+package play
+
+import (
+	"bytes"
+	"encoding/json/jsontext"
+	"strings"
+	"testing"
+)
+
+// NumbersToStrings reads a value from dec and writes it to enc replacing every number with a string
+// holding the exact textual representation of the number.
+func NumbersToStrings(dec *jsontext.Decoder, enc *jsontext.Encoder) error {
+	return streamHooks(dec, enc, []decodeHook{numberToString})
+}
+
+func numberToString(_ *jsontext.Decoder, tok jsontext.Token, emit func(jsontext.Token) error) error {
+	if tok.Kind() == '0' {
+		return emit(jsontext.String(tok.String()))
+	}
+	return emit(tok)
+}
+
+func TestNumbersToStrings(t *testing.T) {
+	type testCase struct {
+		in       string
+		expected string
+	}
+	for _, tc := range []testCase{
+		{`{"id":9007199254740993}`, `{"id":"9007199254740993"}`},
+		{`[1.50, -0, 1e+300, "1", true, null, {"2": 3}]`, `["1.50","-0","1e+300","1",true,null,{"2":"3"}]`},
+		{`123456789012345678901234567890`, `"123456789012345678901234567890"`},
+	} {
+		t.Run(tc.in, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := NumbersToStrings(jsontext.NewDecoder(strings.NewReader(tc.in)), jsontext.NewEncoder(&buf))
+			if err != nil {
+				panic(err)
+			}
+			if got := strings.TrimSpace(buf.String()); got != tc.expected {
+				t.Errorf("not equal: expected(%s) != actual(%s)", tc.expected, got)
+			}
+		})
+	}
+}
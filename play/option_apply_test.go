@@ -0,0 +1,73 @@
+package play
+
+import (
+	"encoding/json/v2"
+	"testing"
+)
+
+// ApplyOption returns the value of patch if it is Some, or current otherwise.
+func ApplyOption[V any](current V, patch Option[V]) V {
+	if patch.IsSome() {
+		return patch.Value()
+	}
+	return current
+}
+
+// ApplyUnd applies patch to current as a PATCH request does:
+// undefined keeps current, null resets it to the zero value, and defined sets the value.
+func ApplyUnd[V any](current V, patch Und[V]) V {
+	switch {
+	case patch.IsUndefined():
+		return current
+	case patch.IsNull():
+		return *new(V)
+	}
+	return patch.Value()
+}
+
+func TestApplyUnd(t *testing.T) {
+	if got := ApplyOption(1, None[int]()); got != 1 {
+		t.Errorf("none should keep current: %d", got)
+	}
+	if got := ApplyOption(1, Some(0)); got != 0 {
+		t.Errorf("some should set the value: %d", got)
+	}
+
+	type testCase struct {
+		name     string
+		patch    Und[string]
+		expected string
+	}
+	for _, tc := range []testCase{
+		{"undefined", Undefined[string](), "current"},
+		{"null", Null[string](), ""},
+		{"defined", Defined("patched"), "patched"},
+		{"defined zero", Defined(""), ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := ApplyUnd("current", tc.patch); got != tc.expected {
+				t.Errorf("not equal: expected(%q) != actual(%q)", tc.expected, got)
+			}
+		})
+	}
+
+	type user struct {
+		Name  string
+		Email string
+		Age   int
+	}
+	type userPatch struct {
+		Name  Und[string] `json:"name,omitzero"`
+		Email Und[string] `json:"email,omitzero"`
+		Age   Und[int]    `json:"age,omitzero"`
+	}
+	var p userPatch
+	if err := json.Unmarshal([]byte(`{"email":null,"age":31}`), &p); err != nil {
+		panic(err)
+	}
+	u := user{"alice", "alice@example.com", 30}
+	u = user{ApplyUnd(u.Name, p.Name), ApplyUnd(u.Email, p.Email), ApplyUnd(u.Age, p.Age)}
+	if expected := (user{"alice", "", 31}); u != expected {
+		t.Errorf("not equal: expected(%#v) != actual(%#v)", expected, u)
+	}
+}
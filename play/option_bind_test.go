@@ -0,0 +1,93 @@
+package play
+
+import (
+	"strconv"
+	"testing"
+)
+
+// Bind calls f with the value of a, or returns None without calling f if a is None.
+func Bind[A, C any](a Option[A], f func(A) Option[C]) Option[C] {
+	if a.IsNone() {
+		return None[C]()
+	}
+	return f(a.Value())
+}
+
+// Bind2 calls f with the values of a and b, or returns None without calling f if any of them is None.
+func Bind2[A, B, C any](a Option[A], b Option[B], f func(A, B) Option[C]) Option[C] {
+	if a.IsNone() || b.IsNone() {
+		return None[C]()
+	}
+	return f(a.Value(), b.Value())
+}
+
+// Bind3 calls f with the values of a, b and c, or returns None without calling f if any of them is None.
+func Bind3[A, B, C, D any](a Option[A], b Option[B], c Option[C], f func(A, B, C) Option[D]) Option[D] {
+	if a.IsNone() || b.IsNone() || c.IsNone() {
+		return None[D]()
+	}
+	return f(a.Value(), b.Value(), c.Value())
+}
+
+func TestOptionBind(t *testing.T) {
+	var called int
+	join := func(a string, b int, c bool) Option[string] {
+		called++
+		return Some(a + strconv.Itoa(b) + strconv.FormatBool(c))
+	}
+
+	type testCase struct {
+		a        Option[string]
+		b        Option[int]
+		c        Option[bool]
+		expected Option[string]
+	}
+	for _, tc := range []testCase{
+		{Some("foo"), Some(1), Some(true), Some("foo1true")},
+		{None[string](), Some(1), Some(true), None[string]()},
+		{Some("foo"), None[int](), Some(true), None[string]()},
+		{Some("foo"), Some(1), None[bool](), None[string]()},
+	} {
+		called = 0
+		result := Bind3(tc.a, tc.b, tc.c, join)
+		if result != tc.expected {
+			t.Errorf("not equal: expected(%#v) != actual(%#v)", tc.expected, result)
+		}
+		if result.IsNone() && called > 0 {
+			t.Errorf("combiner should not be called")
+		}
+	}
+
+	called = 0
+	result := Bind2(Some("foo"), None[int](), func(a string, b int) Option[string] {
+		called++
+		return join(a, b, false)
+	})
+	if result.IsSome() || called > 0 {
+		t.Errorf("incorrect: %#v, called = %d", result, called)
+	}
+	result = Bind2(Some("foo"), Some(2), func(a string, b int) Option[string] {
+		return join(a, b, false)
+	})
+	if result != Some("foo2false") {
+		t.Errorf("incorrect: %#v", result)
+	}
+
+	// f may also yield None.
+	parse := func(s string) Option[int] {
+		i, err := strconv.Atoi(s)
+		if err != nil {
+			return None[int]()
+		}
+		return Some(i)
+	}
+	if r := Bind(Some("12"), parse); r != Some(12) {
+		t.Errorf("incorrect: %#v", r)
+	}
+	if r := Bind(Some("foo"), parse); r.IsSome() {
+		t.Errorf("incorrect: %#v", r)
+	}
+	if r := Bind(None[string](), parse); r.IsSome() {
+		t.Errorf("incorrect: %#v", r)
+	}
+}
@@ -0,0 +1,60 @@
+package play
+
+import (
+	"slices"
+	"testing"
+)
+
+// CollectOptions returns Some of all values if every element of opts is Some, or None otherwise.
+// Empty opts yields Some of an empty slice.
+func CollectOptions[V any](opts []Option[V]) Option[[]V] {
+	values := make([]V, 0, len(opts))
+	for _, o := range opts {
+		if o.IsNone() {
+			return None[[]V]()
+		}
+		values = append(values, o.Value())
+	}
+	return Some(values)
+}
+
+// PartialCollect returns values of Some elements of opts, skipping None.
+func PartialCollect[V any](opts []Option[V]) []V {
+	values := make([]V, 0, len(opts))
+	for _, o := range opts {
+		if o.IsSome() {
+			values = append(values, o.Value())
+		}
+	}
+	return values
+}
+
+func TestCollectOptions(t *testing.T) {
+	type testCase struct {
+		name     string
+		in       []Option[int]
+		expected Option[[]int]
+		partial  []int
+	}
+	for _, tc := range []testCase{
+		{"all some", []Option[int]{Some(1), Some(2), Some(3)}, Some([]int{1, 2, 3}), []int{1, 2, 3}},
+		{"one none", []Option[int]{Some(1), None[int](), Some(3)}, None[[]int](), []int{1, 3}},
+		{"all none", []Option[int]{None[int](), None[int]()}, None[[]int](), []int{}},
+		{"empty", []Option[int]{}, Some([]int{}), []int{}},
+		{"nil", nil, Some([]int{}), []int{}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			collected := CollectOptions(tc.in)
+			if collected.IsSome() != tc.expected.IsSome() || !slices.Equal(collected.Value(), tc.expected.Value()) {
+				t.Errorf("not equal: expected(%#v) != actual(%#v)", tc.expected, collected)
+			}
+			if tc.expected.IsSome() && collected.Value() == nil {
+				t.Errorf("Some should hold a non-nil slice")
+			}
+			partial := PartialCollect(tc.in)
+			if !slices.Equal(partial, tc.partial) {
+				t.Errorf("not equal: expected(%#v) != actual(%#v)", tc.partial, partial)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package play
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// Compare returns a negative number, zero or a positive number as o is less than, equal to or greater than other.
+// None sorts before Some, and two Somes are compared by compare.
+func (o Option[V]) Compare(other Option[V], compare func(a, b V) int) int {
+	switch {
+	case o.IsNone() && other.IsNone():
+		return 0
+	case o.IsNone():
+		return -1
+	case other.IsNone():
+		return 1
+	}
+	return compare(o.Value(), other.Value())
+}
+
+// CompareOrdered is Option.Compare for an ordered V, comparing values by cmp.Compare.
+// It can be passed to slices.SortFunc as is.
+func CompareOrdered[V cmp.Ordered](a, b Option[V]) int {
+	return a.Compare(b, cmp.Compare[V])
+}
+
+// Equal reports whether o and other are both None, or both Some with values eq reports equal.
+// eq lets V be non-comparable, e.g. a slice or a map.
+func (o Option[V]) Equal(other Option[V], eq func(a, b V) bool) bool {
+	if o.IsNone() || other.IsNone() {
+		return o.IsNone() == other.IsNone()
+	}
+	return eq(o.Value(), other.Value())
+}
+
+// EqualOption is Option.Equal for a comparable V, comparing values by ==.
+func EqualOption[V comparable](a, b Option[V]) bool {
+	return a.Equal(b, func(a, b V) bool { return a == b })
+}
+
+func TestOptionCompare(t *testing.T) {
+	opts := []Option[int]{Some(3), None[int](), Some(-1), Some(3), None[int](), Some(0)}
+	slices.SortFunc(opts, CompareOrdered)
+	expected := []Option[int]{None[int](), None[int](), Some(-1), Some(0), Some(3), Some(3)}
+	if !slices.Equal(opts, expected) {
+		t.Errorf("not equal: expected(%#v) != actual(%#v)", expected, opts)
+	}
+
+	// descending, case-insensitively; None is still the first.
+	strs := []Option[string]{Some("b"), Some("A"), None[string](), Some("c")}
+	slices.SortFunc(strs, func(a, b Option[string]) int {
+		return a.Compare(b, func(a, b string) int { return -strings.Compare(strings.ToLower(a), strings.ToLower(b)) })
+	})
+	expectedStrs := []Option[string]{None[string](), Some("c"), Some("b"), Some("A")}
+	if !slices.Equal(strs, expectedStrs) {
+		t.Errorf("not equal: expected(%#v) != actual(%#v)", expectedStrs, strs)
+	}
+
+	type testCase struct {
+		a, b     Option[int]
+		expected int
+	}
+	for _, tc := range []testCase{
+		{None[int](), None[int](), 0},
+		{None[int](), Some(0), -1},
+		{Some(0), None[int](), 1},
+		{Some(1), Some(1), 0},
+		{Some(1), Some(2), -1},
+	} {
+		if got := CompareOrdered(tc.a, tc.b); got != tc.expected {
+			t.Errorf("Compare(%#v, %#v): expected(%d) != actual(%d)", tc.a, tc.b, tc.expected, got)
+		}
+	}
+}
+
+func TestOptionEqual(t *testing.T) {
+	type testCase struct {
+		name     string
+		a, b     Option[[]int]
+		expected bool
+	}
+	for _, tc := range []testCase{
+		{"none == none", None[[]int](), None[[]int](), true},
+		{"some != none", Some([]int{1}), None[[]int](), false},
+		{"none != some", None[[]int](), Some([]int(nil)), false},
+		{"some == some", Some([]int{1, 2}), Some([]int{1, 2}), true},
+		{"some != some", Some([]int{1, 2}), Some([]int{2, 1}), false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.a.Equal(tc.b, slices.Equal); got != tc.expected {
+				t.Errorf("not equal: expected(%t) != actual(%t)", tc.expected, got)
+			}
+		})
+	}
+
+	if !EqualOption(None[string](), None[string]()) || EqualOption(Some(""), None[string]()) ||
+		!EqualOption(Some("a"), Some("a")) || EqualOption(Some("a"), Some("b")) {
+		t.Errorf("incorrect EqualOption")
+	}
+}
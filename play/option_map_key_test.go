@@ -0,0 +1,82 @@
+package play
+
+import (
+	"encoding/json/v2"
+	"errors"
+	"net/netip"
+	"testing"
+)
+
+func TestOption_mapKey(t *testing.T) {
+	addr := netip.MustParseAddr("192.0.2.1")
+
+	type testCase struct {
+		name     string
+		in       any
+		target   func() any
+		expected string
+	}
+	for _, tc := range []testCase{
+		{
+			"string",
+			map[Option[string]]int{Some("a"): 1},
+			func() any { return new(map[Option[string]]int) },
+			`{"a":1}`,
+		},
+		{
+			"int",
+			map[Option[int]]int{Some(5): 1},
+			func() any { return new(map[Option[int]]int) },
+			`{"5":1}`,
+		},
+		{
+			"text marshaler",
+			map[Option[netip.Addr]]int{Some(addr): 1},
+			func() any { return new(map[Option[netip.Addr]]int) },
+			`{"192.0.2.1":1}`,
+		},
+		{
+			"text marshaler value",
+			struct{ Addr Option[netip.Addr] }{Some(addr)},
+			func() any { return new(struct{ Addr Option[netip.Addr] }) },
+			`{"Addr":"192.0.2.1"}`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			bin, err := json.Marshal(tc.in, json.Deterministic(true))
+			if err != nil {
+				panic(err)
+			}
+			if string(bin) != tc.expected {
+				t.Errorf("not equal: expected(%s) != actual(%s)", tc.expected, string(bin))
+			}
+			v := tc.target()
+			if err := json.Unmarshal(bin, v); err != nil {
+				panic(err)
+			}
+			bin2, err := json.Marshal(v, json.Deterministic(true))
+			if err != nil {
+				panic(err)
+			}
+			if string(bin2) != tc.expected {
+				t.Errorf("not round-tripped: expected(%s) != actual(%s)", tc.expected, string(bin2))
+			}
+		})
+	}
+
+	_, err := json.Marshal(map[Option[string]]int{None[string](): 1})
+	if !errors.Is(err, ErrNoneMapKey) {
+		t.Errorf("should be ErrNoneMapKey, but is %v", err)
+	}
+	t.Logf("err = %v", err)
+	// option_map_key_test.go:77: err = json: cannot marshal from Go play.Option[string] after offset 1: None Option used as map key
+
+	// None as a value is still null.
+	bin, err := json.Marshal(map[string]Option[int]{"a": None[int]()})
+	if err != nil {
+		panic(err)
+	}
+	if expected := `{"a":null}`; string(bin) != expected {
+		t.Errorf("not equal: expected(%s) != actual(%s)", expected, string(bin))
+	}
+}
@@ -0,0 +1,146 @@
+package play
+
+import (
+	"encoding/json/v2"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+// MapOption maps the value of o with f, or returns None without calling f if o is None.
+func MapOption[V, U any](o Option[V], f func(V) U) Option[U] {
+	if o.IsNone() {
+		return None[U]()
+	}
+	return Some(f(o.Value()))
+}
+
+func (o Option[V]) Map(f func(V) V) Option[V] {
+	return MapOption(o, f)
+}
+
+// FlatMapOption is an alias of Bind, named after MapOption.
+func FlatMapOption[V, U any](o Option[V], f func(V) Option[U]) Option[U] {
+	return Bind(o, f)
+}
+
+// Filter returns o if o is Some and pred reports true for its value, otherwise None.
+// pred is not called for None.
+func (o Option[V]) Filter(pred func(V) bool) Option[V] {
+	if o.IsNone() || !pred(o.Value()) {
+		return None[V]()
+	}
+	return o
+}
+
+// Tap calls fn with the value of o if o is Some, e.g. for logging, and returns o unchanged.
+func (o Option[V]) Tap(fn func(V)) Option[V] {
+	if o.IsSome() {
+		fn(o.Value())
+	}
+	return o
+}
+
+func TestOptionMap(t *testing.T) {
+	calls := 0
+	double := func(i int) int { calls++; return i * 2 }
+
+	if o := None[int]().Map(double).Map(double); o.IsSome() || calls != 0 {
+		t.Errorf("None should not call f: %#v, calls = %d", o, calls)
+	}
+	if o := Some(3).Map(double).Map(double); o != Some(12) {
+		t.Errorf("not equal: expected(%#v) != actual(%#v)", Some(12), o)
+	}
+	if o := MapOption(Some(5), strconv.Itoa); o != Some("5") {
+		t.Errorf("not equal: expected(%#v) != actual(%#v)", Some("5"), o)
+	}
+
+	parse := func(s string) Option[int] {
+		i, err := strconv.Atoi(s)
+		if err != nil {
+			return None[int]()
+		}
+		return Some(i)
+	}
+	type testCase struct {
+		in       Option[string]
+		expected Option[int]
+	}
+	for _, tc := range []testCase{
+		{None[string](), None[int]()},
+		{Some("foo"), None[int]()},
+		{Some("12"), Some(12)},
+	} {
+		if o := FlatMapOption(tc.in, parse); o != tc.expected {
+			t.Errorf("not equal: expected(%#v) != actual(%#v)", tc.expected, o)
+		}
+	}
+
+	type sample struct {
+		A Option[int] `json:"a"`
+		B Option[int] `json:"b"`
+	}
+	var s sample
+	err := json.Unmarshal([]byte(`{"a":null,"b":2}`), &s)
+	if err != nil {
+		panic(err)
+	}
+	s.A, s.B = s.A.Map(double), s.B.Map(double)
+	bin, err := json.Marshal(s)
+	if err != nil {
+		panic(err)
+	}
+	if expected := `{"a":null,"b":4}`; string(bin) != expected {
+		t.Errorf("not equal: expected(%s) != actual(%s)", expected, string(bin))
+	}
+}
+
+func TestOptionFilter(t *testing.T) {
+	calls := 0
+	positive := func(i int) bool { calls++; return i > 0 }
+
+	if o := None[int]().Filter(positive); o.IsSome() || calls != 0 {
+		t.Errorf("None should not call pred: %#v, calls = %d", o, calls)
+	}
+
+	type sample struct {
+		Age Option[int] `json:"age"`
+	}
+	type testCase struct {
+		in       string
+		expected Option[int]
+	}
+	for _, tc := range []testCase{
+		{`{"age":20}`, Some(40)},
+		{`{"age":-1}`, None[int]()},
+		{`{"age":null}`, None[int]()},
+		{`{}`, None[int]()},
+	} {
+		t.Run(tc.in, func(t *testing.T) {
+			var s sample
+			err := json.Unmarshal([]byte(tc.in), &s)
+			if err != nil {
+				panic(err)
+			}
+			if o := s.Age.Filter(positive).Map(func(i int) int { return i * 2 }); o != tc.expected {
+				t.Errorf("not equal: expected(%#v) != actual(%#v)", tc.expected, o)
+			}
+		})
+	}
+}
+
+func TestOptionTap(t *testing.T) {
+	var seen []int
+	record := func(i int) { seen = append(seen, i) }
+
+	if o := None[int]().Tap(record); o.IsSome() || len(seen) != 0 {
+		t.Errorf("None should not call fn: %#v, seen = %v", o, seen)
+	}
+	o := Some(3).Tap(record).Map(func(i int) int { return i + 1 }).Tap(record).Filter(func(i int) bool { return i > 10 }).Tap(record)
+	if o.IsSome() || !slices.Equal(seen, []int{3, 4}) {
+		t.Errorf("incorrect: %#v, seen = %v", o, seen)
+	}
+	if o := Some(5).Tap(record); o != Some(5) {
+		t.Errorf("not equal: expected(%#v) != actual(%#v)", Some(5), o)
+	}
+}
@@ -0,0 +1,89 @@
+package play
+
+import (
+	"bytes"
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"testing"
+)
+
+// WithOptionNoneAs returns options that marshal None of Option[V] as sentinel instead of null,
+// and unmarshal sentinel (and null) back into None.
+//
+// This is mostly useful for string and text types where a consumer needs Some("") and None to be distinguishable
+// while the field can not be omitted.
+// If omission is acceptable, tagging the field with omitzero is enough: None is omitted and Some("") is written as "".
+//
+// sentinel must be a valid JSON value which V never marshals into.
+func WithOptionNoneAs[V any](sentinel jsontext.Value) json.Options {
+	sentinel = sentinel.Clone()
+	if err := sentinel.Compact(); err != nil {
+		panic(err)
+	}
+	return json.JoinOptions(
+		json.WithMarshalers(json.MarshalToFunc(func(enc *jsontext.Encoder, o Option[V]) error {
+			if o.IsNone() {
+				return enc.WriteValue(sentinel)
+			}
+			return json.MarshalEncode(enc, o.Value())
+		})),
+		json.WithUnmarshalers(json.UnmarshalFromFunc(func(dec *jsontext.Decoder, o *Option[V]) error {
+			val, err := dec.ReadValue()
+			if err != nil {
+				return err
+			}
+			val = val.Clone()
+			if err := val.Compact(); err != nil {
+				return err
+			}
+			if val.Kind() == 'n' || bytes.Equal(val, sentinel) {
+				*o = None[V]()
+				return nil
+			}
+			var v V
+			if err := json.Unmarshal(val, &v, dec.Options()); err != nil {
+				return err
+			}
+			*o = Some(v)
+			return nil
+		})),
+	)
+}
+
+func TestOptionNone(t *testing.T) {
+	type sample struct {
+		Foo Option[string] `json:",omitzero"`
+		Bar Option[string]
+	}
+
+	type testCase struct {
+		in        sample
+		opts      []json.Options
+		marshaled string
+	}
+	for _, tc := range []testCase{
+		{sample{None[string](), None[string]()}, nil, `{"Bar":null}`},
+		{sample{Some(""), Some("")}, nil, `{"Foo":"","Bar":""}`},
+		{sample{None[string](), None[string]()}, []json.Options{WithOptionNoneAs[string](jsontext.Value(`false`))}, `{"Bar":false}`},
+		{sample{Some(""), Some("")}, []json.Options{WithOptionNoneAs[string](jsontext.Value(`false`))}, `{"Foo":"","Bar":""}`},
+		{sample{Some("foo"), None[string]()}, []json.Options{WithOptionNoneAs[string](jsontext.Value(`{ }`))}, `{"Foo":"foo","Bar":{}}`},
+	} {
+		t.Run(tc.marshaled, func(t *testing.T) {
+			bin, err := json.Marshal(tc.in, tc.opts...)
+			if err != nil {
+				panic(err)
+			}
+			if string(bin) != tc.marshaled {
+				t.Errorf("not equal: expected(%q) != actual(%q)", tc.marshaled, string(bin))
+			}
+			var unmarshaled sample
+			err = json.Unmarshal(bin, &unmarshaled, tc.opts...)
+			if err != nil {
+				panic(err)
+			}
+			if unmarshaled != tc.in {
+				t.Errorf("not equal:\nexpected(%#v)\n!=\nactual(%#v)", tc.in, unmarshaled)
+			}
+		})
+	}
+}
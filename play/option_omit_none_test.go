@@ -0,0 +1,177 @@
+package play
+
+import (
+	"encoding"
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"errors"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// OptionOmitNone returns options that omit a None Option struct field by default,
+// and write null for it only if the field is tagged `option:"emitnull"`:
+//
+//	type T struct {
+//		Omitted Option[int] `json:"omitted"`
+//		Nulled  Option[int] `json:"nulled" option:"emitnull"`
+//	}
+//
+// A pointer to Option is not affected; a nil pointer is still written as null.
+// The json `format` tag option can not be used for this, since json v2 rejects it for types other than its built-in ones.
+//
+// It works by converting the struct to an identical type whose Option fields have omitzero added to their json tag,
+// so structs with unexported or embedded fields, or with custom marshal methods, are marshaled as is.
+func OptionOmitNone() json.Options {
+	return json.WithMarshalers(json.MarshalToFunc(func(enc *jsontext.Encoder, v any) error {
+		rv := reflect.ValueOf(v)
+		// Addressable values are passed as pointers.
+		if rv.Kind() == reflect.Pointer && !rv.IsNil() {
+			rv = rv.Elem()
+		}
+		if rv.Kind() != reflect.Struct {
+			return errors.ErrUnsupported
+		}
+		converted := optionOmitNoneTypeFor(rv.Type())
+		if converted == nil {
+			return errors.ErrUnsupported
+		}
+		return json.MarshalEncode(enc, rv.Convert(converted).Interface())
+	}))
+}
+
+type noneable interface {
+	IsNone() bool
+}
+
+var marshalerTypes = []reflect.Type{
+	reflect.TypeFor[json.MarshalerTo](),
+	reflect.TypeFor[json.Marshaler](),
+	reflect.TypeFor[encoding.TextMarshaler](),
+}
+
+// optionOmitNoneCache caches results of optionOmitNoneTypeFor keyed by reflect.Type of the struct.
+var optionOmitNoneCache sync.Map
+
+// optionOmitNoneTypeFor returns a struct type identical to t except for omitzero added to tags of Option fields,
+// or nil if there is no field to change or t is not supported.
+// Since conversion ignores tags, a value of t can be converted to the returned type.
+func optionOmitNoneTypeFor(t reflect.Type) reflect.Type {
+	if c, ok := optionOmitNoneCache.Load(t); ok {
+		c, _ := c.(reflect.Type)
+		return c
+	}
+	c := optionOmitNoneType(t)
+	optionOmitNoneCache.Store(t, c)
+	return c
+}
+
+func optionOmitNoneType(t reflect.Type) reflect.Type {
+	if slices.ContainsFunc(marshalerTypes, func(it reflect.Type) bool { return t.Implements(it) || reflect.PointerTo(t).Implements(it) }) {
+		return nil
+	}
+	fields := make([]reflect.StructField, t.NumField())
+	changed := false
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		// reflect.StructOf panics for unexported fields and does not promote methods of embedded ones.
+		if !sf.IsExported() || sf.Anonymous {
+			return nil
+		}
+		fields[i] = sf
+		if sf.Type.Kind() == reflect.Pointer || !sf.Type.Implements(reflect.TypeFor[noneable]()) || sf.Tag.Get("option") == "emitnull" {
+			continue
+		}
+		tag, ok := sf.Tag.Lookup("json")
+		if tag == "-" {
+			continue
+		}
+		if _, opts, _ := strings.Cut(tag, ","); slices.ContainsFunc(strings.Split(opts, ","), func(o string) bool {
+			return o == "omitzero" || o == "omitempty"
+		}) {
+			continue
+		}
+		if ok {
+			old := `json:` + strconv.Quote(tag)
+			fields[i].Tag = reflect.StructTag(strings.Replace(string(sf.Tag), old, `json:`+strconv.Quote(tag+",omitzero"), 1))
+		} else {
+			fields[i].Tag = reflect.StructTag(strings.TrimSpace(`json:",omitzero" ` + string(sf.Tag)))
+		}
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	return reflect.StructOf(fields)
+}
+
+func TestOptionOmitNone(t *testing.T) {
+	type inner struct {
+		V Option[string] `json:"v"`
+	}
+	type sample struct {
+		Omitted  Option[int] `json:"omitted"`
+		Nulled   Option[int] `json:"nulled" option:"emitnull"`
+		Untagged Option[string]
+		Kept     Option[int]   `json:"kept,omitzero"`
+		Plain    string        `json:"plain"`
+		Inner    inner         `json:"inner"`
+		List     []Option[int] `json:"list"`
+		Ptr      *Option[int]  `json:"ptr"`
+	}
+
+	type testCase struct {
+		name     string
+		in       sample
+		expected string
+	}
+	for _, tc := range []testCase{
+		{
+			"none",
+			sample{},
+			`{"nulled":null,"plain":"","inner":{},"list":[],"ptr":null}`,
+		},
+		{
+			"some",
+			sample{
+				Omitted:  Some(1),
+				Nulled:   Some(2),
+				Untagged: Some("u"),
+				Kept:     Some(3),
+				Inner:    inner{Some("v")},
+				List:     []Option[int]{None[int](), Some(4)},
+			},
+			`{"omitted":1,"nulled":2,"Untagged":"u","kept":3,"plain":"","inner":{"v":"v"},"list":[null,4],"ptr":null}`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			bin, err := json.Marshal(tc.in, OptionOmitNone())
+			if err != nil {
+				panic(err)
+			}
+			if string(bin) != tc.expected {
+				t.Errorf("not equal: expected(%s) != actual(%s)", tc.expected, string(bin))
+			}
+			var decoded sample
+			if err := json.Unmarshal(bin, &decoded); err != nil {
+				panic(err)
+			}
+			if decoded.Omitted != tc.in.Omitted || decoded.Nulled != tc.in.Nulled || decoded.Inner != tc.in.Inner {
+				t.Errorf("not round-tripped: expected(%#v) != actual(%#v)", tc.in, decoded)
+			}
+		})
+	}
+
+	// without the option, tags are as usual.
+	bin, err := json.Marshal(inner{})
+	if err != nil {
+		panic(err)
+	}
+	if expected := `{"v":null}`; string(bin) != expected {
+		t.Errorf("not equal: expected(%s) != actual(%s)", expected, string(bin))
+	}
+}
@@ -0,0 +1,56 @@
+package play
+
+import "testing"
+
+// GetOrElse returns the value of o, or fallback if o is None.
+func (o Option[V]) GetOrElse(fallback V) V {
+	if o.IsNone() {
+		return fallback
+	}
+	return o.Value()
+}
+
+// OrElse returns o if o is Some, otherwise other.
+func (o Option[V]) OrElse(other Option[V]) Option[V] {
+	if o.IsNone() {
+		return other
+	}
+	return o
+}
+
+func TestOptionOrElse(t *testing.T) {
+	type testCase struct {
+		o, other Option[int]
+		get      int
+		orElse   Option[int]
+	}
+	for _, tc := range []testCase{
+		{Some(1), Some(2), 1, Some(1)},
+		{Some(0), None[int](), 0, Some(0)},
+		{None[int](), Some(2), -1, Some(2)},
+		{None[int](), None[int](), -1, None[int]()},
+	} {
+		o := tc.o
+		if got := o.GetOrElse(-1); got != tc.get {
+			t.Errorf("GetOrElse: not equal: expected(%d) != actual(%d)", tc.get, got)
+		}
+		if got := o.OrElse(tc.other); got != tc.orElse {
+			t.Errorf("OrElse: not equal: expected(%#v) != actual(%#v)", tc.orElse, got)
+		}
+		if o != tc.o {
+			t.Errorf("receiver mutated: %#v", o)
+		}
+	}
+
+	// nested: only the outer layer is unwrapped.
+	nested := Some(None[string]())
+	if got := nested.GetOrElse(Some("fallback")); got != None[string]() {
+		t.Errorf("not equal: expected(%#v) != actual(%#v)", None[string](), got)
+	}
+	if got := nested.GetOrElse(Some("fallback")).GetOrElse("inner"); got != "inner" {
+		t.Errorf("not equal: expected(%s) != actual(%s)", "inner", got)
+	}
+	if got := None[Option[string]]().OrElse(Some(Some("x"))).GetOrElse(None[string]()); got != Some("x") {
+		t.Errorf("not equal: expected(%#v) != actual(%#v)", Some("x"), got)
+	}
+}
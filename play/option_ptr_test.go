@@ -0,0 +1,58 @@
+package play
+
+import (
+	"encoding/json/v2"
+	"testing"
+)
+
+// OptionFromPtr returns None for nil, otherwise Some of *p.
+func OptionFromPtr[V any](p *V) Option[V] {
+	if p == nil {
+		return None[V]()
+	}
+	return Some(*p)
+}
+
+// Ptr returns nil for None, otherwise a pointer to a freshly allocated copy of the value.
+// Writing through the pointer never affects o.
+func (o Option[V]) Ptr() *V {
+	if o.IsNone() {
+		return nil
+	}
+	v := o.Value()
+	return &v
+}
+
+func TestOptionPtr(t *testing.T) {
+	if o := OptionFromPtr[int](nil); o.IsSome() {
+		t.Errorf("should be None: %#v", o)
+	}
+	i := 5
+	o := OptionFromPtr(&i)
+	i = 6
+	if o != Some(5) {
+		t.Errorf("not equal: expected(%#v) != actual(%#v)", Some(5), o)
+	}
+
+	if p := None[int]().Ptr(); p != nil {
+		t.Errorf("should be nil: %v", p)
+	}
+	p := o.Ptr()
+	*p = 10
+	if o.Value() != 5 || *o.Ptr() != 5 || o.Ptr() == p {
+		t.Errorf("Ptr should return a fresh copy: %#v", o)
+	}
+
+	type sample struct {
+		Nil  Option[string] `json:"nil"`
+		Some Option[string] `json:"some"`
+	}
+	s := "foo"
+	bin, err := json.Marshal(sample{Nil: OptionFromPtr[string](nil), Some: OptionFromPtr(&s)})
+	if err != nil {
+		panic(err)
+	}
+	if expected := `{"nil":null,"some":"foo"}`; string(bin) != expected {
+		t.Errorf("not equal: expected(%s) != actual(%s)", expected, string(bin))
+	}
+}
@@ -0,0 +1,37 @@
+package play
+
+import (
+	"iter"
+	"slices"
+	"testing"
+)
+
+// Seq returns an iterator yielding the value of o once if o is Some, or nothing if o is None.
+func (o Option[V]) Seq() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		if o.IsSome() {
+			yield(o.Value())
+		}
+	}
+}
+
+func TestOptionSeq(t *testing.T) {
+	opts := []Option[int]{Some(1), None[int](), Some(0), None[int](), Some(3)}
+
+	var values []int
+	for _, o := range opts {
+		for v := range o.Seq() {
+			values = append(values, v)
+		}
+	}
+	if expected := []int{1, 0, 3}; !slices.Equal(values, expected) {
+		t.Errorf("not equal: expected(%v) != actual(%v)", expected, values)
+	}
+
+	if collected := slices.Collect(None[string]().Seq()); len(collected) != 0 {
+		t.Errorf("None should yield nothing: %v", collected)
+	}
+	if collected := slices.Collect(Some("foo").Seq()); !slices.Equal(collected, []string{"foo"}) {
+		t.Errorf("Some should yield once: %v", collected)
+	}
+}
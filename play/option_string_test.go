@@ -0,0 +1,56 @@
+package play
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+var (
+	_ fmt.Stringer   = Option[any]{}
+	_ fmt.GoStringer = Option[any]{}
+)
+
+// String returns "Some(<v>)" formatting the value with %v, or "None".
+func (o Option[V]) String() string {
+	if o.IsNone() {
+		return "None"
+	}
+	return fmt.Sprintf("Some(%v)", o.Value())
+}
+
+// GoString returns o as a Go expression constructing it, e.g. play.Some[int](3) or play.None[int]().
+func (o Option[V]) GoString() string {
+	typ := reflect.TypeFor[V]().String()
+	if o.IsNone() {
+		return fmt.Sprintf("play.None[%s]()", typ)
+	}
+	return fmt.Sprintf("play.Some[%s](%#v)", typ, o.Value())
+}
+
+func TestOptionString(t *testing.T) {
+	type point struct {
+		X, Y int
+	}
+	type testCase struct {
+		in       any
+		str      string
+		goString string
+	}
+	for _, tc := range []testCase{
+		{Some(3), "Some(3)", "play.Some[int](3)"},
+		{None[int](), "None", "play.None[int]()"},
+		{Some("foo"), "Some(foo)", `play.Some[string]("foo")`},
+		{Some(point{1, 2}), "Some({1 2})", "play.Some[play.point](play.point{X:1, Y:2})"},
+		{Some(Some(1.5)), "Some(Some(1.5))", "play.Some[play.Option[float64]](play.Some[float64](1.5))"},
+		{Some(None[string]()), "Some(None)", "play.Some[play.Option[string]](play.None[string]())"},
+		{[]Option[int]{Some(1), None[int]()}, "[Some(1) None]", "[]play.Option[int]{play.Some[int](1), play.None[int]()}"},
+	} {
+		if s := fmt.Sprintf("%v", tc.in); s != tc.str {
+			t.Errorf("not equal: expected(%s) != actual(%s)", tc.str, s)
+		}
+		if s := fmt.Sprintf("%#v", tc.in); s != tc.goString {
+			t.Errorf("not equal: expected(%s) != actual(%s)", tc.goString, s)
+		}
+	}
+}
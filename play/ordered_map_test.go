@@ -0,0 +1,197 @@
+package play
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"fmt"
+	"iter"
+	"slices"
+	"testing"
+)
+
+var (
+	_ json.MarshalerTo     = OrderedMap[string, any]{}
+	_ json.UnmarshalerFrom = (*OrderedMap[string, any])(nil)
+)
+
+// OrderedMap is a map which remembers insertion order of keys,
+// and marshals to and unmarshals from a JSON object keeping member order.
+// zero value is an empty map ready to use.
+type OrderedMap[K ~string, V any] struct {
+	keys []K
+	m    map[K]V
+}
+
+func (m *OrderedMap[K, V]) Len() int {
+	return len(m.keys)
+}
+
+func (m *OrderedMap[K, V]) Get(k K) (V, bool) {
+	v, ok := m.m[k]
+	return v, ok
+}
+
+// Set sets v to k. A new key is appended to the end, and an existing key keeps its position.
+func (m *OrderedMap[K, V]) Set(k K, v V) {
+	if m.m == nil {
+		m.m = make(map[K]V)
+	}
+	if _, ok := m.m[k]; !ok {
+		m.keys = append(m.keys, k)
+	}
+	m.m[k] = v
+}
+
+func (m *OrderedMap[K, V]) Delete(k K) {
+	if _, ok := m.m[k]; !ok {
+		return
+	}
+	delete(m.m, k)
+	m.keys = slices.DeleteFunc(m.keys, func(kk K) bool { return kk == k })
+}
+
+func (m *OrderedMap[K, V]) Keys() iter.Seq[K] {
+	return slices.Values(m.keys)
+}
+
+func (m *OrderedMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for _, k := range m.keys {
+			if !yield(k, m.m[k]) {
+				return
+			}
+		}
+	}
+}
+
+func (m OrderedMap[K, V]) MarshalJSONTo(enc *jsontext.Encoder) error {
+	if err := enc.WriteToken(jsontext.BeginObject); err != nil {
+		return err
+	}
+	for k, v := range m.All() {
+		if err := enc.WriteToken(jsontext.String(string(k))); err != nil {
+			return err
+		}
+		if err := json.MarshalEncode(enc, v); err != nil {
+			return err
+		}
+	}
+	return enc.WriteToken(jsontext.EndObject)
+}
+
+// UnmarshalJSONFrom reads a JSON object into m, replacing its content. null resets m to the zero value.
+func (m *OrderedMap[K, V]) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	if dec.PeekKind() == 'n' {
+		if err := dec.SkipValue(); err != nil {
+			return err
+		}
+		*m = OrderedMap[K, V]{}
+		return nil
+	}
+	if err := readBegin(dec, '{'); err != nil {
+		return err
+	}
+	var decoded OrderedMap[K, V]
+	for dec.PeekKind() != '}' {
+		tok, err := dec.ReadToken()
+		if err != nil {
+			return err
+		}
+		k := K(tok.String())
+		var v V
+		if err := json.UnmarshalDecode(dec, &v); err != nil {
+			return err
+		}
+		decoded.Set(k, v)
+	}
+	if _, err := dec.ReadToken(); err != nil {
+		return err
+	}
+	*m = decoded
+	return nil
+}
+
+// StructToOrderedMap marshals struct v and returns its members in an OrderedMap in the marshaled order,
+// which is the struct declaration order, so that members can be inserted, removed or reordered before marshaling again.
+// json tags and options like omitzero are honored as json.Marshal does.
+func StructToOrderedMap(v any, opts ...json.Options) (OrderedMap[string, jsontext.Value], error) {
+	var m OrderedMap[string, jsontext.Value]
+	bin, err := json.Marshal(v, opts...)
+	if err != nil {
+		return m, err
+	}
+	if k := jsontext.Value(bin).Kind(); k != '{' {
+		return m, fmt.Errorf("struct to ordered map: %T marshals to %s, not an object", v, k)
+	}
+	err = json.Unmarshal(bin, &m)
+	return m, err
+}
+
+func TestStructToOrderedMap(t *testing.T) {
+	type sample struct {
+		Zeta    string         `json:"zeta"`
+		Alpha   int            `json:"alpha"`
+		Skipped string         `json:"-"`
+		Middle  Option[string] `json:"middle,omitzero"`
+		Nested  map[string]int `json:"nested"`
+		Beta    bool
+	}
+
+	m, err := StructToOrderedMap(sample{Zeta: "z", Alpha: 1, Skipped: "s", Nested: map[string]int{"a": 1}})
+	if err != nil {
+		panic(err)
+	}
+	keys := slices.Collect(m.Keys())
+	if expected := []string{"zeta", "alpha", "nested", "Beta"}; !slices.Equal(keys, expected) {
+		t.Errorf("not equal: expected(%#v) != actual(%#v)", expected, keys)
+	}
+
+	m.Delete("alpha")
+	m.Set("zeta", jsontext.Value(`"zz"`))
+	m.Set("added", jsontext.Value(`[1,2]`))
+	bin, err := json.Marshal(m)
+	if err != nil {
+		panic(err)
+	}
+	if expected := `{"zeta":"zz","nested":{"a":1},"Beta":false,"added":[1,2]}`; string(bin) != expected {
+		t.Errorf("not equal: expected(%s) != actual(%s)", expected, string(bin))
+	}
+
+	m, err = StructToOrderedMap(&sample{Middle: Some("m")})
+	if err != nil {
+		panic(err)
+	}
+	if v, ok := m.Get("middle"); !ok || string(v) != `"m"` {
+		t.Errorf("incorrect: %s, %t", string(v), ok)
+	}
+	if m.Len() != 5 {
+		t.Errorf("incorrect length: %d", m.Len())
+	}
+
+	_, err = StructToOrderedMap([]int{1})
+	if err == nil {
+		t.Errorf("should cause an error")
+	}
+	t.Logf("err = %v", err)
+}
+
+func TestOrderedMap_null(t *testing.T) {
+	type sample struct {
+		M OrderedMap[string, int] `json:"m"`
+	}
+	var s sample
+	s.M.Set("a", 1)
+	if err := json.Unmarshal([]byte(`{"m":null}`), &s); err != nil {
+		panic(err)
+	}
+	if s.M.Len() != 0 {
+		t.Errorf("should be reset, but is %#v", s.M)
+	}
+
+	if err := json.Unmarshal([]byte(`{"m":{"b":2,"a":1}}`), &s); err != nil {
+		panic(err)
+	}
+	if keys := slices.Collect(s.M.Keys()); !slices.Equal(keys, []string{"b", "a"}) {
+		t.Errorf("incorrect: %#v", keys)
+	}
+}
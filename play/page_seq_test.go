@@ -0,0 +1,160 @@
+package play
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// PageSeq yields elements of paginated JSON arrays, fetching pages lazily as iteration proceeds.
+//
+// fetch is first called with an empty cursor and returns a page, a JSON array, along with the cursor of the next page.
+// Pages are fetched until fetch returns an empty next cursor.
+// Elements are decoded from the page one by one as they are yielded.
+// If the page is an io.Closer, it is closed after its elements are consumed or iteration stops.
+//
+// An error from fetch or decoding is yielded once, and then iteration stops.
+func PageSeq[T any](fetch func(cursor string) (page io.Reader, next string, err error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var cursor string
+		for {
+			page, next, err := fetch(cursor)
+			if err != nil {
+				yield(*new(T), fmt.Errorf("fetch page %q: %w", cursor, err))
+				return
+			}
+			if !yieldPage(page, yield) {
+				return
+			}
+			if next == "" {
+				return
+			}
+			cursor = next
+		}
+	}
+}
+
+// yieldPage yields elements of page and reports whether iteration should continue.
+func yieldPage[T any](page io.Reader, yield func(T, error) bool) bool {
+	if c, ok := page.(io.Closer); ok {
+		defer c.Close()
+	}
+	dec := jsontext.NewDecoder(page)
+	if err := readBegin(dec, '['); err != nil {
+		yield(*new(T), err)
+		return false
+	}
+	for dec.PeekKind() != ']' {
+		var v T
+		if err := json.UnmarshalDecode(dec, &v); err != nil {
+			yield(*new(T), err)
+			return false
+		}
+		if !yield(v, nil) {
+			return false
+		}
+	}
+	if _, err := dec.ReadToken(); err != nil {
+		yield(*new(T), err)
+		return false
+	}
+	return true
+}
+
+type closeRecorder struct {
+	io.Reader
+	closed *int
+}
+
+func (c closeRecorder) Close() error {
+	*c.closed++
+	return nil
+}
+
+func TestPageSeq(t *testing.T) {
+	type user struct {
+		ID int `json:"id"`
+	}
+	type page struct {
+		body string
+		next string
+	}
+	pages := map[string]page{
+		"":   {`[{"id":1},{"id":2}]`, "p2"},
+		"p2": {`[{"id":3}]`, "p3"},
+		"p3": {`[]`, ""},
+	}
+
+	var (
+		fetched []string
+		closed  int
+	)
+	fetch := func(cursor string) (io.Reader, string, error) {
+		fetched = append(fetched, cursor)
+		p, ok := pages[cursor]
+		if !ok {
+			return nil, "", errors.New("no such page")
+		}
+		return closeRecorder{strings.NewReader(p.body), &closed}, p.next, nil
+	}
+
+	var ids []int
+	for u, err := range PageSeq[user](fetch) {
+		if err != nil {
+			panic(err)
+		}
+		ids = append(ids, u.ID)
+	}
+	if !slices.Equal(ids, []int{1, 2, 3}) {
+		t.Errorf("incorrect: %#v", ids)
+	}
+	if !slices.Equal(fetched, []string{"", "p2", "p3"}) || closed != 3 {
+		t.Errorf("incorrect: fetched = %#v, closed = %d", fetched, closed)
+	}
+
+	// stopping early fetches no further page.
+	fetched, closed = nil, 0
+	for u, err := range PageSeq[user](fetch) {
+		if err != nil {
+			panic(err)
+		}
+		if u.ID == 2 {
+			break
+		}
+	}
+	if !slices.Equal(fetched, []string{""}) || closed != 1 {
+		t.Errorf("incorrect: fetched = %#v, closed = %d", fetched, closed)
+	}
+
+	// errors
+	pages["p2"] = page{`[{"id":3},{"id":"foo"}]`, "p3"}
+	var errs []error
+	ids = nil
+	for u, err := range PageSeq[user](fetch) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		ids = append(ids, u.ID)
+	}
+	if !slices.Equal(ids, []int{1, 2, 3}) || len(errs) != 1 {
+		t.Errorf("incorrect: ids = %#v, errs = %v", ids, errs)
+	}
+	t.Logf("err = %v", errs)
+
+	pages[""] = page{`[]`, "missing"}
+	errs = nil
+	for _, err := range PageSeq[user](fetch) {
+		errs = append(errs, err)
+	}
+	if len(errs) != 1 || errs[0] == nil {
+		t.Errorf("incorrect: errs = %v", errs)
+	}
+	t.Logf("err = %v", errs)
+}
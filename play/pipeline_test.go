@@ -0,0 +1,106 @@
+package play
+
+import (
+	"bytes"
+	"encoding/json/jsontext"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// TokenTransform is a stage of Pipeline.
+//
+// Transform is called for each token flowing through the stage.
+// ptr is the pointer of tok in the source document, i.e. not affected by earlier stages;
+// for an object name, it points to the member the name belongs to, and isName is true.
+// emit passes a token to the next stage. A stage may call emit zero or more times,
+// and is responsible for keeping the output well-formed, e.g. dropping a value along with its name.
+type TokenTransform interface {
+	Transform(tok jsontext.Token, ptr jsontext.Pointer, isName bool, emit func(jsontext.Token) error) error
+}
+
+// TokenTransformFunc adapts a function to TokenTransform.
+type TokenTransformFunc func(tok jsontext.Token, ptr jsontext.Pointer, isName bool, emit func(jsontext.Token) error) error
+
+func (f TokenTransformFunc) Transform(tok jsontext.Token, ptr jsontext.Pointer, isName bool, emit func(jsontext.Token) error) error {
+	return f(tok, ptr, isName, emit)
+}
+
+// Pipeline reads a value from dec and writes it to enc passing every token through stages in order, in a single pass.
+func Pipeline(dec *jsontext.Decoder, enc *jsontext.Encoder, stages ...TokenTransform) error {
+	hooks := make([]decodeHook, len(stages))
+	for i, stage := range stages {
+		hooks[i] = func(dec *jsontext.Decoder, tok jsontext.Token, emit func(jsontext.Token) error) error {
+			return stage.Transform(tok, dec.StackPointer(), tok.Kind() == '"' && justReadName(dec), emit)
+		}
+	}
+	return streamHooks(dec, enc, hooks)
+}
+
+func TestPipeline(t *testing.T) {
+	rename := func(from, to string) TokenTransform {
+		return TokenTransformFunc(func(tok jsontext.Token, ptr jsontext.Pointer, isName bool, emit func(jsontext.Token) error) error {
+			if isName && tok.String() == from {
+				return emit(jsontext.String(to))
+			}
+			return emit(tok)
+		})
+	}
+	// redact replaces values at pointers with "***". Containers are redacted as a whole.
+	redact := func(pointers ...jsontext.Pointer) TokenTransform {
+		var depth int // > 0 while dropping a container.
+		return TokenTransformFunc(func(tok jsontext.Token, ptr jsontext.Pointer, isName bool, emit func(jsontext.Token) error) error {
+			if depth > 0 {
+				switch tok.Kind() {
+				case '{', '[':
+					depth++
+				case '}', ']':
+					depth--
+				}
+				return nil
+			}
+			if isName || !slices.Contains(pointers, ptr) {
+				return emit(tok)
+			}
+			if k := tok.Kind(); k == '{' || k == '[' {
+				depth = 1
+			}
+			return emit(jsontext.String("***"))
+		})
+	}
+
+	const input = `{
+    "user": {"name": "alice", "pass": "hunter2", "keys": ["k1", "k2"]},
+    "users": [{"pass": "p1"}, {"pass": "p2"}]
+}`
+
+	type testCase struct {
+		name     string
+		stages   []TokenTransform
+		expected string
+	}
+	for _, tc := range []testCase{
+		{"none", nil, `{"user":{"name":"alice","pass":"hunter2","keys":["k1","k2"]},"users":[{"pass":"p1"},{"pass":"p2"}]}`},
+		{
+			"rename then redact",
+			[]TokenTransform{rename("pass", "password"), redact("/user/pass", "/user/keys", "/users/1/pass")},
+			`{"user":{"name":"alice","password":"***","keys":"***"},"users":[{"password":"p1"},{"password":"***"}]}`,
+		},
+		{
+			"redact then rename",
+			[]TokenTransform{redact("/user"), rename("user", "account"), rename("users", "accounts")},
+			`{"account":"***","accounts":[{"pass":"p1"},{"pass":"p2"}]}`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := Pipeline(jsontext.NewDecoder(strings.NewReader(input)), jsontext.NewEncoder(&buf), tc.stages...)
+			if err != nil {
+				panic(err)
+			}
+			if got := strings.TrimSpace(buf.String()); got != tc.expected {
+				t.Errorf("not equal: expected(%s) != actual(%s)", tc.expected, got)
+			}
+		})
+	}
+}
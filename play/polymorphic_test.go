@@ -0,0 +1,260 @@
+package play
+
+import (
+	"bytes"
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+var (
+	_ json.MarshalerTo     = Polymorphic[any]{}
+	_ json.UnmarshalerFrom = (*Polymorphic[any])(nil)
+)
+
+var ErrUnknownPolymorphicTag = errors.New("unknown polymorphic tag")
+
+type polymorphicRegistry struct {
+	mu        sync.RWMutex
+	tagField  string
+	factories map[string]func() any
+	tags      map[reflect.Type]string
+}
+
+// polymorphicRegistries holds *polymorphicRegistry keyed by reflect.Type of Base.
+var polymorphicRegistries sync.Map
+
+func registryFor[Base any]() *polymorphicRegistry {
+	r, _ := polymorphicRegistries.LoadOrStore(reflect.TypeFor[Base](), &polymorphicRegistry{
+		tagField:  "type",
+		factories: make(map[string]func() any),
+		tags:      make(map[reflect.Type]string),
+	})
+	return r.(*polymorphicRegistry)
+}
+
+// RegisterType registers factory for tag to Polymorphic[Base].
+// factory must return a pointer to a new concrete value, e.g. func() Event { return &Created{} }.
+// Registering the same tag again replaces the previous factory.
+func RegisterType[Base any](tag string, factory func() Base) {
+	r := registryFor[Base]()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.factories[tag] = func() any { return factory() }
+	r.tags[reflect.TypeOf(factory())] = tag
+}
+
+// SetTagField sets the name of the member holding the tag for Polymorphic[Base]. It defaults to "type".
+func SetTagField[Base any](name string) {
+	r := registryFor[Base]()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tagField = name
+}
+
+// Polymorphic holds a value of a concrete type registered by RegisterType as Base.
+// It unmarshals an object by decoding it into the type registered for the value of the tag member,
+// and marshals Value with the tag member prepended.
+type Polymorphic[Base any] struct {
+	Value Base
+}
+
+func (p Polymorphic[Base]) MarshalJSONTo(enc *jsontext.Encoder) error {
+	r := registryFor[Base]()
+	r.mu.RLock()
+	tagField := r.tagField
+	tag, ok := r.tags[reflect.TypeOf(p.Value)]
+	r.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("%w: %T is not registered", ErrUnknownPolymorphicTag, p.Value)
+	}
+
+	bin, err := json.Marshal(p.Value, enc.Options())
+	if err != nil {
+		return err
+	}
+	dec := jsontext.NewDecoder(bytes.NewReader(bin))
+	if err := readBegin(dec, '{'); err != nil {
+		return fmt.Errorf("Polymorphic: %T must marshal to an object: %w", p.Value, err)
+	}
+	if err := enc.WriteToken(jsontext.BeginObject); err != nil {
+		return err
+	}
+	if err := enc.WriteToken(jsontext.String(tagField)); err != nil {
+		return err
+	}
+	if err := enc.WriteToken(jsontext.String(tag)); err != nil {
+		return err
+	}
+	for dec.PeekKind() != '}' {
+		name, err := dec.ReadToken()
+		if err != nil {
+			return err
+		}
+		if name.String() == tagField {
+			if err := dec.SkipValue(); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := enc.WriteToken(name); err != nil {
+			return err
+		}
+		val, err := dec.ReadValue()
+		if err != nil {
+			return err
+		}
+		if err := enc.WriteValue(val); err != nil {
+			return err
+		}
+	}
+	return enc.WriteToken(jsontext.EndObject)
+}
+
+func (p *Polymorphic[Base]) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	if k := dec.PeekKind(); k != '{' {
+		return fmt.Errorf("Polymorphic: expected an object but is %s", k)
+	}
+	val, err := dec.ReadValue()
+	if err != nil {
+		return err
+	}
+
+	r := registryFor[Base]()
+	r.mu.RLock()
+	tagField := r.tagField
+	r.mu.RUnlock()
+
+	tag, err := readTopLevelString(val, tagField)
+	if err != nil {
+		return fmt.Errorf("Polymorphic: tag %q: %w", tagField, err)
+	}
+
+	r.mu.RLock()
+	factory, ok := r.factories[tag]
+	r.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownPolymorphicTag, tag)
+	}
+
+	v := factory()
+	if rv := reflect.ValueOf(v); rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("Polymorphic: factory for %q must return a non-nil pointer, but returned %T", tag, v)
+	}
+	if err := json.Unmarshal(val, v, dec.Options()); err != nil {
+		return err
+	}
+	p.Value = v.(Base)
+	return nil
+}
+
+// readTopLevelString returns the string value of member name of object val.
+// It returns an error wrapping ErrNotFound if val does not have name.
+func readTopLevelString(val jsontext.Value, name string) (string, error) {
+	dec := jsontext.NewDecoder(bytes.NewReader(val))
+	if err := readBegin(dec, '{'); err != nil {
+		return "", err
+	}
+	for dec.PeekKind() != '}' {
+		tok, err := dec.ReadToken()
+		if err != nil {
+			return "", err
+		}
+		if tok.String() != name {
+			if err := dec.SkipValue(); err != nil {
+				return "", err
+			}
+			continue
+		}
+		tok, err = dec.ReadToken()
+		if err != nil {
+			return "", err
+		}
+		if tok.Kind() != '"' {
+			return "", fmt.Errorf("expected string but is %s", tok.Kind())
+		}
+		return tok.String(), nil
+	}
+	return "", ErrNotFound
+}
+
+type polyEvent interface {
+	EventName() string
+}
+
+type polyCreated struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func (e *polyCreated) EventName() string { return "created" }
+
+type polyDeleted struct {
+	ID     int  `json:"id"`
+	Hard   bool `json:"hard"`
+	Reason Option[string]
+}
+
+func (e *polyDeleted) EventName() string { return "deleted" }
+
+func TestPolymorphic(t *testing.T) {
+	RegisterType[polyEvent]("a", func() polyEvent { return &polyCreated{} })
+	RegisterType[polyEvent]("b", func() polyEvent { return &polyDeleted{} })
+
+	var events []Polymorphic[polyEvent]
+	err := json.Unmarshal(
+		[]byte(`[{"type":"a","id":1,"name":"foo"},{"id":2,"hard":true,"type":"b"}]`),
+		&events,
+	)
+	if err != nil {
+		panic(err)
+	}
+	if c, ok := events[0].Value.(*polyCreated); !ok || *c != (polyCreated{1, "foo"}) {
+		t.Errorf("incorrect: %#v", events[0].Value)
+	}
+	if d, ok := events[1].Value.(*polyDeleted); !ok || d.ID != 2 || !d.Hard {
+		t.Errorf("incorrect: %#v", events[1].Value)
+	}
+
+	bin, err := json.Marshal(events)
+	if err != nil {
+		panic(err)
+	}
+	if expected := `[{"type":"a","id":1,"name":"foo"},{"type":"b","id":2,"hard":true,"Reason":null}]`; string(bin) != expected {
+		t.Errorf("not equal: expected(%s) != actual(%s)", expected, string(bin))
+	}
+
+	type testCase struct {
+		in       string
+		expected error
+	}
+	for _, tc := range []testCase{
+		{`{"type":"c","id":1}`, ErrUnknownPolymorphicTag},
+		{`{"id":1}`, ErrNotFound},
+	} {
+		t.Run(tc.in, func(t *testing.T) {
+			var p Polymorphic[polyEvent]
+			err := json.Unmarshal([]byte(tc.in), &p)
+			if !errors.Is(err, tc.expected) {
+				t.Errorf("should be %v, but is %v", tc.expected, err)
+			}
+			t.Logf("err = %v", err)
+		})
+	}
+
+	type kinded interface{}
+	SetTagField[kinded]("kind")
+	RegisterType[kinded]("created", func() kinded { return &polyCreated{} })
+	var p Polymorphic[kinded]
+	err = json.Unmarshal([]byte(`{"kind":"created","id":3}`), &p)
+	if err != nil {
+		panic(err)
+	}
+	if c, ok := p.Value.(*polyCreated); !ok || c.ID != 3 {
+		t.Errorf("incorrect: %#v", p.Value)
+	}
+}
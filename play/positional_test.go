@@ -0,0 +1,238 @@
+package play
+
+import (
+	"bytes"
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+var ErrArity = errors.New("arity mismatch")
+
+// positionalFields returns indices of fields of struct type t which are encoded positionally,
+// which are exported fields not tagged with `json:"-"`, in declaration order.
+func positionalFields(t reflect.Type) []int {
+	var indices []int
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		if !sf.IsExported() || sf.Tag.Get("json") == "-" {
+			continue
+		}
+		indices = append(indices, i)
+	}
+	return indices
+}
+
+// UnmarshalPositional decodes a JSON array into exported fields of the struct v points to, in declaration order:
+// the element at 0 into the first field, and so on.
+// Fields tagged with `json:"-"` are skipped.
+//
+// An array shorter than the fields leaves trailing fields untouched.
+// An array longer than the fields is an error wrapping ErrArity.
+func UnmarshalPositional(data []byte, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("unmarshal positional: v must be a non-nil pointer to a struct, but is %T", v)
+	}
+	rv = rv.Elem()
+	fields := positionalFields(rv.Type())
+
+	dec := jsontext.NewDecoder(bytes.NewReader(data))
+	if err := readBegin(dec, '['); err != nil {
+		return err
+	}
+	for i := 0; dec.PeekKind() != ']'; i++ {
+		if i >= len(fields) {
+			return fmt.Errorf("%w: %s has %d fields, but array has more elements", ErrArity, rv.Type(), len(fields))
+		}
+		f := rv.Field(fields[i])
+		if err := json.UnmarshalDecode(dec, f.Addr().Interface()); err != nil {
+			return fmt.Errorf("position %d (%s): %w", i, rv.Type().Field(fields[i]).Name, err)
+		}
+	}
+	if _, err := dec.ReadToken(); err != nil {
+		return err
+	}
+	return checkTrailingData(dec)
+}
+
+// hasTagOption reports whether the json tag of sf has opt after its name.
+func hasTagOption(sf reflect.StructField, opt string) bool {
+	_, opts, _ := strings.Cut(sf.Tag.Get("json"), ",")
+	return slices.Contains(strings.Split(opts, ","), opt)
+}
+
+// MarshalPositional encodes exported fields of struct v as a JSON array in declaration order,
+// the counterpart of UnmarshalPositional.
+// Fields tagged with `json:"-"` are skipped.
+//
+// Since positions matter, omitzero and omitempty only drop trailing fields.
+// A field in the middle which would be omitted is written as null to hold its position.
+func MarshalPositional(v any) ([]byte, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("marshal positional: v must be a struct or a pointer to a struct, but is %T", v)
+	}
+
+	var (
+		values  []jsontext.Value
+		omitted []bool
+	)
+	for _, i := range positionalFields(rv.Type()) {
+		sf, f := rv.Type().Field(i), rv.Field(i)
+		val, err := json.Marshal(f.Interface())
+		if err != nil {
+			return nil, fmt.Errorf("position %d (%s): %w", len(values), sf.Name, err)
+		}
+		omit := false
+		if hasTagOption(sf, "omitzero") {
+			if z, ok := f.Interface().(interface{ IsZero() bool }); ok {
+				omit = z.IsZero()
+			} else {
+				omit = f.IsZero()
+			}
+		}
+		if hasTagOption(sf, "omitempty") {
+			switch string(val) {
+			case `null`, `""`, `[]`, `{}`:
+				omit = true
+			}
+		}
+		values = append(values, val)
+		omitted = append(omitted, omit)
+	}
+	for len(omitted) > 0 && omitted[len(omitted)-1] {
+		values, omitted = values[:len(values)-1], omitted[:len(omitted)-1]
+	}
+
+	var buf bytes.Buffer
+	enc := jsontext.NewEncoder(&buf)
+	if err := enc.WriteToken(jsontext.BeginArray); err != nil {
+		return nil, err
+	}
+	for i, val := range values {
+		if omitted[i] {
+			val = jsontext.Value(`null`)
+		}
+		if err := enc.WriteValue(val); err != nil {
+			return nil, err
+		}
+	}
+	if err := enc.WriteToken(jsontext.EndArray); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSpace(buf.Bytes()), nil
+}
+
+func TestUnmarshalPositional(t *testing.T) {
+	type point struct {
+		X      float64
+		Y      float64
+		Label  string
+		hidden int
+	}
+
+	var p point
+	err := UnmarshalPositional([]byte(`[1.5, -2, "origin"]`), &p)
+	if err != nil {
+		panic(err)
+	}
+	if p != (point{1.5, -2, "origin", 0}) {
+		t.Errorf("incorrect: %#v", p)
+	}
+
+	p = point{Label: "kept"}
+	err = UnmarshalPositional([]byte(`[3, 4]`), &p)
+	if err != nil {
+		panic(err)
+	}
+	if p != (point{3, 4, "kept", 0}) {
+		t.Errorf("incorrect: %#v", p)
+	}
+
+	err = UnmarshalPositional([]byte(`[1, 2, "foo", 4]`), &p)
+	if !errors.Is(err, ErrArity) {
+		t.Errorf("should be ErrArity, but is %v", err)
+	}
+	t.Logf("err = %v", err)
+	// positional_test.go:165: err = arity mismatch: play.point has 3 fields, but array has more elements
+
+	err = UnmarshalPositional([]byte(`[1, "foo"]`), &p)
+	if err == nil {
+		t.Errorf("should cause an error")
+	}
+	t.Logf("err = %v", err)
+	// positional_test.go:172: err = position 1 (Y): json: cannot unmarshal JSON string into Go float64 within "/1"
+
+	err = UnmarshalPositional([]byte(`[1, 2] 3`), &p)
+	if !errors.Is(err, ErrTrailingData) {
+		t.Errorf("should be ErrTrailingData, but is %v", err)
+	}
+
+	type record struct {
+		ID      int
+		Ignored string `json:"-"`
+		Tags    []string
+		Owner   Option[string]
+	}
+	var r record
+	err = UnmarshalPositional([]byte(`[1, ["a","b"], null]`), &r)
+	if err != nil {
+		panic(err)
+	}
+	if r.ID != 1 || len(r.Tags) != 2 || r.Owner.IsSome() {
+		t.Errorf("incorrect: %#v", r)
+	}
+}
+
+func TestMarshalPositional(t *testing.T) {
+	type record struct {
+		ID      int
+		Name    string         `json:",omitempty"`
+		Ignored string         `json:"-"`
+		Owner   Option[string] `json:",omitzero"`
+		Tags    []string       `json:",omitempty"`
+		Count   int            `json:",omitzero"`
+	}
+
+	type testCase struct {
+		in       record
+		expected string
+	}
+	for _, tc := range []testCase{
+		{record{1, "foo", "ignored", Some("bar"), []string{"a"}, 2}, `[1,"foo","bar",["a"],2]`},
+		{record{ID: 1, Name: "foo"}, `[1,"foo"]`},
+		{record{ID: 1, Count: 2}, `[1,null,null,null,2]`},
+		{record{ID: 1, Owner: Some("")}, `[1,null,""]`},
+		{record{}, `[0]`},
+	} {
+		t.Run(tc.expected, func(t *testing.T) {
+			bin, err := MarshalPositional(tc.in)
+			if err != nil {
+				panic(err)
+			}
+			if string(bin) != tc.expected {
+				t.Errorf("not equal: expected(%s) != actual(%s)", tc.expected, string(bin))
+			}
+
+			var unmarshaled record
+			err = UnmarshalPositional(bin, &unmarshaled)
+			if err != nil {
+				panic(err)
+			}
+			expected := tc.in
+			expected.Ignored = ""
+			if !reflect.DeepEqual(expected, unmarshaled) {
+				t.Errorf("not equal:\nexpected(%#v)\n!=\nactual(%#v)", expected, unmarshaled)
+			}
+		})
+	}
+}
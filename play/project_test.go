@@ -0,0 +1,170 @@
+package play
+
+import (
+	"bytes"
+	"encoding/json/jsontext"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type projectFrame struct {
+	kind jsontext.Kind
+	// name of the container in its parent object, if hasName.
+	name    string
+	hasName bool
+	written bool
+	// number of elements of an array skipped since the last written one.
+	skipped int
+}
+
+// Project reads a value from dec and writes to enc only the values at pointers in keep,
+// along with the objects and arrays enclosing them so that each kept value stays at the same pointer.
+// A pointer contained by another pointer in keep is redundant.
+//
+// Array elements that are skipped before a kept element are written as null to preserve indices.
+// Skipped trailing elements are dropped.
+// If nothing is kept, the top-level value is written as an empty object or array, or null for a scalar.
+func Project(dec *jsontext.Decoder, enc *jsontext.Encoder, keep []jsontext.Pointer) error {
+	var frames []*projectFrame
+
+	// ensure writes enclosing containers not yet written.
+	ensure := func() error {
+		for _, f := range frames {
+			if !f.written {
+				if f.hasName {
+					if err := enc.WriteToken(jsontext.String(f.name)); err != nil {
+						return err
+					}
+				}
+				begin := jsontext.BeginObject
+				if f.kind == '[' {
+					begin = jsontext.BeginArray
+				}
+				if err := enc.WriteToken(begin); err != nil {
+					return err
+				}
+				f.written = true
+			}
+			for ; f.skipped > 0; f.skipped-- {
+				if err := enc.WriteToken(jsontext.Null); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	skipped := func() {
+		if len(frames) > 0 {
+			if parent := frames[len(frames)-1]; parent.kind == '[' {
+				parent.skipped++
+			}
+		}
+	}
+
+	var walk func(p jsontext.Pointer, name string, hasName bool) error
+	walk = func(p jsontext.Pointer, name string, hasName bool) error {
+		if slices.ContainsFunc(keep, func(k jsontext.Pointer) bool { return k.Contains(p) }) {
+			if err := ensure(); err != nil {
+				return err
+			}
+			if hasName {
+				if err := enc.WriteToken(jsontext.String(name)); err != nil {
+					return err
+				}
+			}
+			val, err := dec.ReadValue()
+			if err != nil {
+				return err
+			}
+			return enc.WriteValue(val)
+		}
+
+		kind := dec.PeekKind()
+		isRoot := len(frames) == 0
+		if (kind != '{' && kind != '[') ||
+			!isRoot && !slices.ContainsFunc(keep, func(k jsontext.Pointer) bool { return p.Contains(k) }) {
+			if err := dec.SkipValue(); err != nil {
+				return err
+			}
+			if isRoot {
+				return enc.WriteToken(jsontext.Null)
+			}
+			skipped()
+			return nil
+		}
+
+		if _, err := dec.ReadToken(); err != nil {
+			return err
+		}
+		f := &projectFrame{kind: kind, name: name, hasName: hasName}
+		frames = append(frames, f)
+		if isRoot {
+			if err := ensure(); err != nil {
+				return err
+			}
+		}
+		for i := 0; dec.PeekKind() != '}' && dec.PeekKind() != ']'; i++ {
+			if kind == '[' {
+				if err := walk(p.AppendToken(strconv.Itoa(i)), "", false); err != nil {
+					return err
+				}
+				continue
+			}
+			tok, err := dec.ReadToken()
+			if err != nil {
+				return err
+			}
+			if err := walk(p.AppendToken(tok.String()), tok.String(), true); err != nil {
+				return err
+			}
+		}
+		end, err := dec.ReadToken()
+		if err != nil {
+			return err
+		}
+		frames = frames[:len(frames)-1]
+		if !f.written {
+			skipped()
+			return nil
+		}
+		return enc.WriteToken(end)
+	}
+
+	return walk("", "", false)
+}
+
+func TestProject(t *testing.T) {
+	const input = `{
+    "user": {"id": 1, "name": "alice", "email": "alice@example.com", "tags": ["a", "b", "c"]},
+    "items": [{"id": 1, "price": 10}, {"id": 2, "price": 20}, {"id": 3, "price": 30}],
+    "meta": {"version": 2}
+}`
+
+	type testCase struct {
+		keep     []jsontext.Pointer
+		expected string
+	}
+	for _, tc := range []testCase{
+		{[]jsontext.Pointer{"/user/name", "/user/id"}, `{"user":{"id":1,"name":"alice"}}`},
+		{[]jsontext.Pointer{"/user", "/user/name"}, `{"user":{"id":1,"name":"alice","email":"alice@example.com","tags":["a","b","c"]}}`},
+		{[]jsontext.Pointer{"/items/1/price"}, `{"items":[null,{"price":20}]}`},
+		{[]jsontext.Pointer{"/user/tags/2", "/meta"}, `{"user":{"tags":[null,null,"c"]},"meta":{"version":2}}`},
+		{[]jsontext.Pointer{"/user/unknown", "/nope"}, `{}`},
+		{nil, `{}`},
+		{[]jsontext.Pointer{""}, `{"user":{"id":1,"name":"alice","email":"alice@example.com","tags":["a","b","c"]},"items":[{"id":1,"price":10},{"id":2,"price":20},{"id":3,"price":30}],"meta":{"version":2}}`},
+	} {
+		t.Run(fmt.Sprint(tc.keep), func(t *testing.T) {
+			var buf bytes.Buffer
+			err := Project(jsontext.NewDecoder(strings.NewReader(input)), jsontext.NewEncoder(&buf), tc.keep)
+			if err != nil {
+				panic(err)
+			}
+			if got := strings.TrimSpace(buf.String()); got != tc.expected {
+				t.Errorf("not equal: expected(%s) != actual(%s)", tc.expected, got)
+			}
+		})
+	}
+}
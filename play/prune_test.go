@@ -0,0 +1,133 @@
+package play
+
+import (
+	"bytes"
+	"encoding/json/jsontext"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type PruneOptions struct {
+	// Nulls drops null values.
+	Nulls bool
+	// EmptyObjects drops objects having no member.
+	EmptyObjects bool
+	// EmptyArrays drops arrays having no element.
+	EmptyArrays bool
+}
+
+// Prune reads a value from dec and writes it to enc dropping values selected by opts,
+// both object members and array elements.
+// Pruning cascades: a container left empty after pruning its contents is dropped as well if selected.
+// The top-level value is always written even if it would be dropped.
+//
+// Each container is buffered until its end since whether it is dropped is known only then.
+func Prune(dec *jsontext.Decoder, enc *jsontext.Encoder, opts PruneOptions) error {
+	val, _, err := pruneValue(dec, opts)
+	if err != nil {
+		return err
+	}
+	return enc.WriteValue(val)
+}
+
+func pruneValue(dec *jsontext.Decoder, opts PruneOptions) (val jsontext.Value, drop bool, err error) {
+	kind := dec.PeekKind()
+	if kind != '{' && kind != '[' {
+		val, err := dec.ReadValue()
+		if err != nil {
+			return nil, false, err
+		}
+		return val.Clone(), kind == 'n' && opts.Nulls, nil
+	}
+
+	var buf bytes.Buffer
+	enc := jsontext.NewEncoder(&buf)
+	begin, err := dec.ReadToken()
+	if err != nil {
+		return nil, false, err
+	}
+	if err := enc.WriteToken(begin); err != nil {
+		return nil, false, err
+	}
+	var n int
+	for dec.PeekKind() != '}' && dec.PeekKind() != ']' {
+		var name string
+		if kind == '{' {
+			tok, err := dec.ReadToken()
+			if err != nil {
+				return nil, false, err
+			}
+			name = tok.String()
+		}
+		child, drop, err := pruneValue(dec, opts)
+		if err != nil {
+			return nil, false, err
+		}
+		if drop {
+			continue
+		}
+		if kind == '{' {
+			if err := enc.WriteToken(jsontext.String(name)); err != nil {
+				return nil, false, err
+			}
+		}
+		if err := enc.WriteValue(child); err != nil {
+			return nil, false, err
+		}
+		n++
+	}
+	end, err := dec.ReadToken()
+	if err != nil {
+		return nil, false, err
+	}
+	if err := enc.WriteToken(end); err != nil {
+		return nil, false, err
+	}
+	drop = n == 0 && (kind == '{' && opts.EmptyObjects || kind == '[' && opts.EmptyArrays)
+	return bytes.TrimSpace(buf.Bytes()), drop, nil
+}
+
+func TestPrune(t *testing.T) {
+	const input = `{
+    "id": 1,
+    "name": null,
+    "tags": [],
+    "meta": {"a": null, "b": {}, "c": [null, {}]},
+    "items": [{"x": null}, 1, null, [], {"y": 0}],
+    "empty": ""
+}`
+
+	all := PruneOptions{Nulls: true, EmptyObjects: true, EmptyArrays: true}
+	type testCase struct {
+		in       string
+		opts     PruneOptions
+		expected string
+	}
+	for _, tc := range []testCase{
+		{input, all, `{"id":1,"items":[1,{"y":0}],"empty":""}`},
+		{input, PruneOptions{Nulls: true}, `{"id":1,"tags":[],"meta":{"b":{},"c":[{}]},"items":[{},1,[],{"y":0}],"empty":""}`},
+		{input, PruneOptions{EmptyObjects: true, EmptyArrays: true}, `{"id":1,"name":null,"meta":{"a":null,"c":[null]},"items":[{"x":null},1,null,{"y":0}],"empty":""}`},
+		{input, PruneOptions{}, `{"id":1,"name":null,"tags":[],"meta":{"a":null,"b":{},"c":[null,{}]},"items":[{"x":null},1,null,[],{"y":0}],"empty":""}`},
+		{`{"a":{"b":{"c":null}}}`, all, `{}`},
+		{`null`, all, `null`},
+		{`[[[]]]`, all, `[]`},
+	} {
+		t.Run(fmt.Sprintf("%s_%+v", tc.in, tc.opts), func(t *testing.T) {
+			var buf bytes.Buffer
+			err := Prune(jsontext.NewDecoder(strings.NewReader(tc.in)), jsontext.NewEncoder(&buf), tc.opts)
+			if err != nil {
+				panic(err)
+			}
+			if got := strings.TrimSpace(buf.String()); got != tc.expected {
+				t.Errorf("not equal: expected(%s) != actual(%s)", tc.expected, got)
+			}
+		})
+	}
+
+	var buf bytes.Buffer
+	err := Prune(jsontext.NewDecoder(strings.NewReader(`{"a":[1,`)), jsontext.NewEncoder(&buf), all)
+	if err == nil {
+		t.Errorf("should cause an error")
+	}
+}
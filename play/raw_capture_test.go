@@ -0,0 +1,152 @@
+package play
+
+import (
+	"bytes"
+	"encoding/json/jsontext"
+	"errors"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+// WithRawCapture passes sink each value at any of pointers, encoded from the tokens this hook receives,
+// while the value is decoded as usual.
+// A "*" token in pointers matches any token. The top-level value can not be captured.
+//
+// Tokens are copied to a side encoder as they pass through, so every hook sees every token of a captured value.
+// The captured value is compact: whitespace of the input is not kept.
+func WithRawCapture(pointers []jsontext.Pointer, sink func(ptr jsontext.Pointer, raw jsontext.Value)) DecodeOption {
+	return func(c *decodeConfig) {
+		type capture struct {
+			ptr jsontext.Pointer
+			buf bytes.Buffer
+			enc *jsontext.Encoder
+		}
+		var active []*capture
+		c.hooks = append(c.hooks, func(dec *jsontext.Decoder, tok jsontext.Token, emit func(jsontext.Token) error) error {
+			if err := emit(tok); err != nil {
+				return err
+			}
+			for _, cur := range active {
+				if err := cur.enc.WriteToken(tok); err != nil {
+					return err
+				}
+			}
+			// innermost captures are completed first.
+			for len(active) > 0 && active[len(active)-1].enc.StackDepth() == 0 {
+				cur := active[len(active)-1]
+				active = active[:len(active)-1]
+				sink(cur.ptr, bytes.TrimSpace(cur.buf.Bytes()))
+			}
+			ptr, ok := nextReadPointer(dec)
+			if ok && slices.ContainsFunc(pointers, func(p jsontext.Pointer) bool { return matchPointer(p, ptr) }) {
+				cur := &capture{ptr: ptr}
+				cur.enc = jsontext.NewEncoder(&cur.buf, dec.Options())
+				active = append(active, cur)
+			}
+			return nil
+		})
+	}
+}
+
+// nextReadPointer returns the pointer of the value dec reads next, if it is an object member value or an array element.
+func nextReadPointer(dec *jsontext.Decoder) (jsontext.Pointer, bool) {
+	kind, length := dec.StackIndex(dec.StackDepth())
+	switch {
+	case kind == '{' && length%2 == 1:
+		return dec.StackPointer(), true
+	case kind == '[' && dec.PeekKind() != ']':
+		// StackPointer points to the last read element, or to the array itself if none.
+		parent := dec.StackPointer()
+		if length > 0 {
+			parent = parent[:len(parent)-len(parent.LastToken())-1]
+		}
+		return parent.AppendToken(strconv.FormatInt(length, 10)), true
+	}
+	return "", false
+}
+
+func TestDecodeOption_RawCapture(t *testing.T) {
+	type payload struct {
+		ID   int    `json:"id"`
+		Body string `json:"body"`
+	}
+	type sample struct {
+		Payload   payload `json:"payload"`
+		Signature string  `json:"signature"`
+		List      []int   `json:"list"`
+	}
+
+	input := []byte(`{
+    "payload": {"id": 1,  "body": "  hello  "},
+    "signature": "c2lna",
+    "list": [1, 2,   3]
+}`)
+
+	captured := make(map[jsontext.Pointer]string)
+	sink := func(ptr jsontext.Pointer, raw jsontext.Value) {
+		captured[ptr] = string(raw)
+	}
+
+	var s sample
+	err := UnmarshalWith(
+		input,
+		&s,
+		WithRawCapture([]jsontext.Pointer{"/signature", "/payload", "/list/*"}, sink),
+		WithTrimStrings(),
+	)
+	if err != nil {
+		panic(err)
+	}
+	expected := sample{payload{1, "hello"}, "c2lna", []int{1, 2, 3}}
+	if s.Payload != expected.Payload || s.Signature != expected.Signature || !slices.Equal(s.List, expected.List) {
+		t.Errorf("not equal: expected(%#v) != actual(%#v)", expected, s)
+	}
+	expectedCaptured := map[jsontext.Pointer]string{
+		"/payload":   `{"id":1,"body":"  hello  "}`,
+		"/signature": `"c2lna"`,
+		"/list/0":    `1`,
+		"/list/1":    `2`,
+		"/list/2":    `3`,
+	}
+	if len(captured) != len(expectedCaptured) {
+		t.Errorf("not equal: expected(%#v) != actual(%#v)", expectedCaptured, captured)
+	}
+	for ptr, raw := range expectedCaptured {
+		if captured[ptr] != raw {
+			t.Errorf("%q: not equal: expected(%s) != actual(%s)", ptr, raw, captured[ptr])
+		}
+	}
+}
+
+func TestDecodeOption_RawCapture_hooks(t *testing.T) {
+	var captured []string
+	sink := func(ptr jsontext.Pointer, raw jsontext.Value) {
+		captured = append(captured, string(ptr)+" "+string(raw))
+	}
+	capture := WithRawCapture([]jsontext.Pointer{"/p", "/p/inner"}, sink)
+
+	var m map[string]map[string]any
+	err := UnmarshalWith([]byte(`{"p":{" k ":" v ","inner":{"score":1}}}`), &m, capture, WithTrimStrings())
+	if err != nil {
+		panic(err)
+	}
+	if v, ok := m["p"][" k "]; !ok || v != "v" {
+		t.Errorf("incorrect: %#v", m)
+	}
+	expected := []string{`/p/inner {"score":1}`, `/p {" k ":" v ","inner":{"score":1}}`}
+	if !slices.Equal(captured, expected) {
+		t.Errorf("not equal: expected(%q) != actual(%q)", expected, captured)
+	}
+
+	err = UnmarshalWith([]byte(`{"p":{"score":500}}`), &m, capture, WithNumberRange(0, 100, "/p/score"))
+	if !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("should be ErrOutOfRange, but is %v", err)
+	}
+
+	// {, "p", {, "score", 1, }, }
+	err = UnmarshalWith([]byte(`{"p":{"score":1}}`), &m, WithMaxTokens(6), capture)
+	if !errors.Is(err, ErrTooManyTokens) {
+		t.Errorf("should be ErrTooManyTokens, but is %v", err)
+	}
+}
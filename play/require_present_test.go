@@ -0,0 +1,118 @@
+package play
+
+import (
+	"encoding/json/jsontext"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// justReadName reports whether the token dec has just read is an object member name.
+func justReadName(dec *jsontext.Decoder) bool {
+	kind, n := dec.StackIndex(dec.StackDepth())
+	return kind == '{' && n%2 == 1
+}
+
+type MissingPointersError struct {
+	Pointers []jsontext.Pointer
+}
+
+func (e *MissingPointersError) Error() string {
+	var b strings.Builder
+	b.WriteString("missing required pointers: ")
+	for i, p := range e.Pointers {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, "%q", p)
+	}
+	return b.String()
+}
+
+// RequirePresent reads a value from dec and returns *MissingPointersError listing every pointer in required
+// that does not point to a value in the document.
+// A member whose value is null is present.
+//
+// The document is read once. Members that can not contain any of required are skipped.
+func RequirePresent(dec *jsontext.Decoder, required []jsontext.Pointer) error {
+	missing := slices.Clone(required)
+	markPresent := func(p jsontext.Pointer) {
+		missing = slices.DeleteFunc(missing, func(r jsontext.Pointer) bool { return r == p })
+	}
+	mayContain := func(p jsontext.Pointer) bool {
+		return slices.ContainsFunc(missing, func(r jsontext.Pointer) bool { return p.Contains(r) })
+	}
+
+	depth := dec.StackDepth()
+	for {
+		tok, err := dec.ReadToken()
+		if err != nil {
+			return err
+		}
+		p := dec.StackPointer()
+		markPresent(p)
+		if tok.Kind() == '"' && justReadName(dec) && !mayContain(p) {
+			if err := dec.SkipValue(); err != nil {
+				return err
+			}
+		}
+		if dec.StackDepth() == depth {
+			break
+		}
+	}
+
+	if len(missing) > 0 {
+		return &MissingPointersError{Pointers: missing}
+	}
+	return nil
+}
+
+func TestRequirePresent(t *testing.T) {
+	const input = `{
+    "user": {
+        "name": "foo",
+        "email": null,
+        "tags": ["a", "b"]
+    },
+    "meta": {"nested": {"deep": [1, 2, {"x": 1}]}}
+}`
+
+	type testCase struct {
+		required []jsontext.Pointer
+		missing  []jsontext.Pointer
+	}
+	for _, tc := range []testCase{
+		{[]jsontext.Pointer{""}, nil},
+		{[]jsontext.Pointer{"/user/name", "/user/email", "/user/tags/1", "/meta/nested/deep/2/x"}, nil},
+		{
+			[]jsontext.Pointer{"/user/name", "/user/id", "/user/tags/2", "/meta"},
+			[]jsontext.Pointer{"/user/id", "/user/tags/2"},
+		},
+		{[]jsontext.Pointer{"/meta/nested/deep/2/y", "/foo"}, []jsontext.Pointer{"/meta/nested/deep/2/y", "/foo"}},
+	} {
+		t.Run(fmt.Sprintf("%v", tc.required), func(t *testing.T) {
+			dec := jsontext.NewDecoder(strings.NewReader(input))
+			err := RequirePresent(dec, tc.required)
+			if tc.missing == nil {
+				if err != nil {
+					t.Errorf("should be nil, but is %v", err)
+				}
+				return
+			}
+			var missingErr *MissingPointersError
+			if !errors.As(err, &missingErr) {
+				t.Fatalf("should be *MissingPointersError, but is %v", err)
+			}
+			if !slices.Equal(tc.missing, missingErr.Pointers) {
+				t.Errorf("not equal: expected(%v) != actual(%v)", tc.missing, missingErr.Pointers)
+			}
+			t.Logf("err = %v", err)
+			// require_present_test.go:111: err = missing required pointers: "/user/id", "/user/tags/2"
+			if dec.StackDepth() != 0 {
+				t.Errorf("should consume entire value")
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package play
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// Result holds either a value or an error, e.g. the outcome of decoding one element of a batch.
+// The zero value is Ok of zero T.
+type Result[T any] struct {
+	v   T
+	err error
+}
+
+func Ok[T any](v T) Result[T] {
+	return Result[T]{v: v}
+}
+
+// Fail returns a Result holding err. err must not be nil.
+func Fail[T any](err error) Result[T] {
+	return Result[T]{err: err}
+}
+
+func (r Result[T]) IsOk() bool {
+	return r.err == nil
+}
+
+func (r Result[T]) Value() T {
+	return r.v
+}
+
+func (r Result[T]) Err() error {
+	return r.err
+}
+
+func (r Result[T]) Unpack() (T, error) {
+	return r.v, r.err
+}
+
+// CollectResults returns Ok of all values if every element of rs is Ok, or the first error otherwise.
+// Empty rs yields Ok of an empty slice.
+// It is the error-aware counterpart of CollectOptions.
+func CollectResults[T any](rs []Result[T]) Result[[]T] {
+	values := make([]T, 0, len(rs))
+	for _, r := range rs {
+		if !r.IsOk() {
+			return Fail[[]T](r.Err())
+		}
+		values = append(values, r.Value())
+	}
+	return Ok(values)
+}
+
+func TestCollectResults(t *testing.T) {
+	errFirst := errors.New("first")
+	errSecond := errors.New("second")
+
+	type testCase struct {
+		name     string
+		in       []Result[int]
+		expected Result[[]int]
+	}
+	for _, tc := range []testCase{
+		{"all ok", []Result[int]{Ok(1), Ok(2), Ok(3)}, Ok([]int{1, 2, 3})},
+		{"first error", []Result[int]{Ok(1), Fail[int](errFirst), Ok(3), Fail[int](errSecond)}, Fail[[]int](errFirst)},
+		{"empty", []Result[int]{}, Ok([]int{})},
+		{"nil", nil, Ok([]int{})},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			collected := CollectResults(tc.in)
+			if collected.Err() != tc.expected.Err() || !slices.Equal(collected.Value(), tc.expected.Value()) {
+				t.Errorf("not equal: expected(%#v) != actual(%#v)", tc.expected, collected)
+			}
+			if tc.expected.IsOk() && collected.Value() == nil {
+				t.Errorf("Ok should hold a non-nil slice")
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package play
+
+import (
+	"bytes"
+	"encoding/json/jsontext"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// Sample reads an array from dec and writes to enc an array of every every-th element of it,
+// starting from the first element. Other elements are skipped without being decoded.
+func Sample(dec *jsontext.Decoder, enc *jsontext.Encoder, every int) error {
+	if every <= 0 {
+		return fmt.Errorf("sample: every must be positive, but is %d", every)
+	}
+	if err := readBegin(dec, '['); err != nil {
+		return err
+	}
+	if err := enc.WriteToken(jsontext.BeginArray); err != nil {
+		return err
+	}
+	for i := 0; dec.PeekKind() != ']'; i++ {
+		if i%every != 0 {
+			if err := dec.SkipValue(); err != nil {
+				return err
+			}
+			continue
+		}
+		val, err := dec.ReadValue()
+		if err != nil {
+			return err
+		}
+		if err := enc.WriteValue(val); err != nil {
+			return err
+		}
+	}
+	if _, err := dec.ReadToken(); err != nil {
+		return err
+	}
+	return enc.WriteToken(jsontext.EndArray)
+}
+
+func TestSample(t *testing.T) {
+	type testCase struct {
+		in       string
+		every    int
+		expected string
+	}
+	for _, tc := range []testCase{
+		{`[0,1,2,3,4,5,6,7,8,9]`, 3, `[0,3,6,9]`},
+		{`[0,1,2,3,4,5,6,7,8,9]`, 1, `[0,1,2,3,4,5,6,7,8,9]`},
+		{`[0,1,2,3,4,5,6,7,8,9]`, 20, `[0]`},
+		{`[{"a":[1,2]},{"b":null},{"c":"d"}]`, 2, `[{"a":[1,2]},{"c":"d"}]`},
+		{`[]`, 3, `[]`},
+	} {
+		t.Run(fmt.Sprintf("%s_%d", tc.in, tc.every), func(t *testing.T) {
+			var buf bytes.Buffer
+			err := Sample(jsontext.NewDecoder(strings.NewReader(tc.in)), jsontext.NewEncoder(&buf), tc.every)
+			if err != nil {
+				panic(err)
+			}
+			if got := strings.TrimSpace(buf.String()); got != tc.expected {
+				t.Errorf("not equal: expected(%s) != actual(%s)", tc.expected, got)
+			}
+		})
+	}
+
+	for _, every := range []int{0, -1} {
+		var buf bytes.Buffer
+		err := Sample(jsontext.NewDecoder(strings.NewReader(`[1]`)), jsontext.NewEncoder(&buf), every)
+		if err == nil {
+			t.Errorf("should cause an error: every = %d", every)
+		}
+	}
+	var buf bytes.Buffer
+	err := Sample(jsontext.NewDecoder(strings.NewReader(`{"a":1}`)), jsontext.NewEncoder(&buf), 1)
+	if err == nil {
+		t.Errorf("should cause an error for non array")
+	}
+}
@@ -0,0 +1,81 @@
+package play
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+// WithSingletonArrays returns options that let any slice accept a single non-array value,
+// unmarshaling it into a slice with one element.
+// Arrays and null are unmarshaled as usual.
+//
+// []byte is left as is, since it is unmarshaled from a base64 string.
+func WithSingletonArrays() json.Options {
+	return json.WithUnmarshalers(json.UnmarshalFromFunc(func(dec *jsontext.Decoder, v any) error {
+		rv := reflect.ValueOf(v).Elem()
+		if rv.Kind() != reflect.Slice || rv.Type().Elem().Kind() == reflect.Uint8 {
+			return errors.ErrUnsupported
+		}
+		switch dec.PeekKind() {
+		case 0, '[', 'n':
+			return errors.ErrUnsupported
+		}
+		elem := reflect.New(rv.Type().Elem())
+		if err := json.UnmarshalDecode(dec, elem.Interface()); err != nil {
+			return err
+		}
+		s := reflect.MakeSlice(rv.Type(), 1, 1)
+		s.Index(0).Set(elem.Elem())
+		rv.Set(s)
+		return nil
+	}))
+}
+
+func TestSingletonArrays(t *testing.T) {
+	type item struct {
+		Name string
+	}
+	type sample struct {
+		Tags  []string `json:"tags"`
+		Items []item   `json:"items"`
+		Bin   []byte   `json:"bin"`
+	}
+
+	type testCase struct {
+		in       string
+		expected sample
+	}
+	for _, tc := range []testCase{
+		{`{"tags":"x"}`, sample{Tags: []string{"x"}}},
+		{`{"tags":["x","y"]}`, sample{Tags: []string{"x", "y"}}},
+		{`{"tags":null}`, sample{}},
+		{`{"items":{"Name":"foo"}}`, sample{Items: []item{{"foo"}}}},
+		{`{"items":[{"Name":"foo"}]}`, sample{Items: []item{{"foo"}}}},
+		{`{"bin":"Zm9v"}`, sample{Bin: []byte("foo")}},
+	} {
+		t.Run(tc.in, func(t *testing.T) {
+			var s sample
+			err := json.Unmarshal([]byte(tc.in), &s, WithSingletonArrays())
+			if err != nil {
+				panic(err)
+			}
+			if !slices.Equal(s.Tags, tc.expected.Tags) ||
+				!slices.Equal(s.Items, tc.expected.Items) ||
+				!slices.Equal(s.Bin, tc.expected.Bin) {
+				t.Errorf("not equal:\nexpected(%#v)\n!=\nactual(%#v)", tc.expected, s)
+			}
+		})
+	}
+
+	var s sample
+	err := json.Unmarshal([]byte(`{"tags":"x"}`), &s)
+	if err == nil {
+		t.Errorf("should cause an error without the option")
+	}
+	t.Logf("err = %v", err)
+	// singleton_arrays_test.go:79: err = json: cannot unmarshal JSON string into Go []string within "/tags"
+}
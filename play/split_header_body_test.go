@@ -0,0 +1,202 @@
+package play
+
+import (
+	"bytes"
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// SplitHeaderBody reads an envelope object from dec in a single pass,
+// calling onHeader with dec positioned at the value at headerPointer,
+// then onBody once per element of the array at bodyPointer.
+// Each call must consume exactly one value from dec.
+//
+// Both pointers must be reached through object members only.
+// If the body precedes the header in the input, the body is buffered until the header is read,
+// so onHeader always sees the header before any body element.
+// An absent header or body is an error wrapping ErrNotFound.
+func SplitHeaderBody(
+	dec *jsontext.Decoder,
+	headerPointer jsontext.Pointer,
+	bodyPointer jsontext.Pointer,
+	onHeader func(*jsontext.Decoder) error,
+	onBody func(*jsontext.Decoder) error,
+) error {
+	s := &headerBodySplitter{
+		header:   headerPointer,
+		body:     bodyPointer,
+		onHeader: onHeader,
+		onBody:   onBody,
+	}
+	if err := readBegin(dec, '{'); err != nil {
+		return err
+	}
+	if err := s.walkObject(dec); err != nil {
+		return err
+	}
+	if !s.headerRead {
+		return fmt.Errorf("header %q: %w", headerPointer, ErrNotFound)
+	}
+	if !s.bodyRead {
+		if s.buffered == nil {
+			return fmt.Errorf("body %q: %w", bodyPointer, ErrNotFound)
+		}
+		return s.streamBody(jsontext.NewDecoder(bytes.NewReader(s.buffered), dec.Options()))
+	}
+	return nil
+}
+
+type headerBodySplitter struct {
+	header, body         jsontext.Pointer
+	onHeader, onBody     func(*jsontext.Decoder) error
+	headerRead, bodyRead bool
+	// buffered is the body read before the header.
+	buffered jsontext.Value
+}
+
+// walkObject walks members of an object whose '{' is already read, up to and including its '}'.
+func (s *headerBodySplitter) walkObject(dec *jsontext.Decoder) error {
+	for dec.PeekKind() != '}' {
+		if _, err := dec.ReadToken(); err != nil {
+			return err
+		}
+		ptr := dec.StackPointer()
+		var err error
+		switch {
+		case ptr == s.header:
+			err = callOnValue(dec, s.onHeader)
+			s.headerRead = true
+		case ptr == s.body && !s.headerRead:
+			s.buffered, err = dec.ReadValue()
+			s.buffered = s.buffered.Clone()
+		case ptr == s.body:
+			err = s.streamBody(dec)
+			s.bodyRead = true
+		case dec.PeekKind() == '{' && (ptr.Contains(s.header) || ptr.Contains(s.body)):
+			if _, err = dec.ReadToken(); err == nil {
+				err = s.walkObject(dec)
+			}
+		default:
+			err = dec.SkipValue()
+		}
+		if err != nil {
+			return fmt.Errorf("%q: %w", ptr, err)
+		}
+	}
+	_, err := dec.ReadToken()
+	return err
+}
+
+func (s *headerBodySplitter) streamBody(dec *jsontext.Decoder) error {
+	if err := readBegin(dec, '['); err != nil {
+		return err
+	}
+	for dec.PeekKind() != ']' {
+		if err := callOnValue(dec, s.onBody); err != nil {
+			return err
+		}
+	}
+	_, err := dec.ReadToken()
+	return err
+}
+
+// callOnValue calls fn with dec positioned at a value and checks that fn consumed exactly that value.
+func callOnValue(dec *jsontext.Decoder, fn func(*jsontext.Decoder) error) error {
+	depth := dec.StackDepth()
+	_, length := dec.StackIndex(depth)
+	if err := fn(dec); err != nil {
+		return err
+	}
+	if _, after := dec.StackIndex(depth); dec.StackDepth() != depth || after != length+1 {
+		return errors.New("callback must consume exactly one value")
+	}
+	return nil
+}
+
+func TestSplitHeaderBody(t *testing.T) {
+	type meta struct {
+		Total int    `json:"total"`
+		Unit  string `json:"unit"`
+	}
+	type item struct {
+		ID   int `json:"id"`
+		Size int `json:"size"`
+	}
+
+	type testCase struct {
+		name   string
+		in     string
+		header jsontext.Pointer
+		body   jsontext.Pointer
+	}
+	for _, tc := range []testCase{
+		{
+			"header first",
+			`{"meta":{"total":2,"unit":"KiB"},"skipped":[1,{"a":2}],"items":[{"id":1,"size":3},{"id":2,"size":4}]}`,
+			"/meta", "/items",
+		},
+		{
+			"body first",
+			`{"items":[{"id":1,"size":3},{"id":2,"size":4}],"meta":{"total":2,"unit":"KiB"}}`,
+			"/meta", "/items",
+		},
+		{
+			"nested",
+			`{"v":1,"data":{"items":[{"id":1,"size":3},{"id":2,"size":4}],"x":null},"info":{"meta":{"total":2,"unit":"KiB"}}}`,
+			"/info/meta", "/data/items",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				m     meta
+				lines []string
+			)
+			err := SplitHeaderBody(
+				jsontext.NewDecoder(strings.NewReader(tc.in)),
+				tc.header,
+				tc.body,
+				func(dec *jsontext.Decoder) error {
+					return json.UnmarshalDecode(dec, &m)
+				},
+				func(dec *jsontext.Decoder) error {
+					if m.Unit == "" {
+						return fmt.Errorf("item before meta")
+					}
+					var i item
+					if err := json.UnmarshalDecode(dec, &i); err != nil {
+						return err
+					}
+					lines = append(lines, fmt.Sprintf("%d: %d%s", i.ID, i.Size, m.Unit))
+					return nil
+				},
+			)
+			if err != nil {
+				panic(err)
+			}
+			if expected := []string{"1: 3KiB", "2: 4KiB"}; m.Total != 2 || !slices.Equal(lines, expected) {
+				t.Errorf("not equal: expected(%v) != actual(%v), meta = %#v", expected, lines, m)
+			}
+		})
+	}
+
+	noop := func(dec *jsontext.Decoder) error { return dec.SkipValue() }
+	for _, in := range []string{`{"items":[]}`, `{"meta":{}}`} {
+		err := SplitHeaderBody(jsontext.NewDecoder(strings.NewReader(in)), "/meta", "/items", noop, noop)
+		if !errors.Is(err, ErrNotFound) {
+			t.Errorf("should be ErrNotFound, but is %v", err)
+		}
+	}
+	err := SplitHeaderBody(
+		jsontext.NewDecoder(strings.NewReader(`{"meta":{},"items":[1,2]}`)),
+		"/meta", "/items",
+		noop, func(dec *jsontext.Decoder) error { return nil },
+	)
+	if err == nil {
+		t.Errorf("should fail for a callback consuming nothing")
+	}
+}
@@ -0,0 +1,98 @@
+package play
+
+import (
+	"bytes"
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+var ErrTrailingData = errors.New("trailing data")
+
+// StrictComplete decodes exactly one top-level value from r into v.
+// Unlike UnmarshalPrefix, any non-whitespace data after the value is an error wrapping ErrTrailingData,
+// which reports the offset where the trailing data begins.
+func StrictComplete(r io.Reader, v any, opts ...json.Options) error {
+	dec := jsontext.NewDecoder(r)
+	if err := json.UnmarshalDecode(dec, v, opts...); err != nil {
+		return err
+	}
+	return checkTrailingData(dec)
+}
+
+// checkTrailingData returns an error wrapping ErrTrailingData if dec has any non-whitespace data left,
+// reporting the offset where the trailing data begins.
+func checkTrailingData(dec *jsontext.Decoder) error {
+	kind := dec.PeekKind()
+	offset := peekOffset(dec)
+	if kind != 0 {
+		return fmt.Errorf("%w: at offset %d", ErrTrailingData, offset)
+	}
+	_, err := dec.ReadToken()
+	switch {
+	case errors.Is(err, io.EOF):
+		return nil
+	case err == nil:
+		return fmt.Errorf("%w: at offset %d", ErrTrailingData, offset)
+	default:
+		// syntactically invalid trailing data, e.g. garbage or a comment.
+		return fmt.Errorf("%w: at offset %d: %w", ErrTrailingData, offset, err)
+	}
+}
+
+// peekOffset returns the offset of the token dec has peeked.
+// PeekKind skips whitespace but does not consume it, so InputOffset is before the whitespace.
+func peekOffset(dec *jsontext.Decoder) int64 {
+	unread := dec.UnreadBuffer()
+	return dec.InputOffset() + int64(len(unread)-len(bytes.TrimLeft(unread, " \t\r\n")))
+}
+
+func TestStrictComplete(t *testing.T) {
+	type sample struct {
+		A int `json:"a"`
+	}
+
+	for _, in := range []string{`{"a":1}`, "{\"a\":1} \r\n\t", "  {\"a\":1}\n"} {
+		var s sample
+		err := StrictComplete(strings.NewReader(in), &s)
+		if err != nil {
+			t.Errorf("%q: should not cause an error: %v", in, err)
+		}
+		if s.A != 1 {
+			t.Errorf("%q: incorrect: %#v", in, s)
+		}
+	}
+
+	type testCase struct {
+		in     string
+		offset string
+	}
+	for _, tc := range []testCase{
+		{`{"a":1} {"b":2}`, "offset 8"},
+		{`{"a":1}{"b":2}`, "offset 7"},
+		{"{\"a\":1}\n  garbage", "offset 10:"},
+		{`{"a":1} // comment`, "offset 8:"},
+	} {
+		t.Run(tc.in, func(t *testing.T) {
+			var s sample
+			err := StrictComplete(strings.NewReader(tc.in), &s)
+			if !errors.Is(err, ErrTrailingData) {
+				t.Fatalf("should be ErrTrailingData, but is %v", err)
+			}
+			if !strings.Contains(err.Error(), tc.offset) {
+				t.Errorf("error should report %s, but is %v", tc.offset, err)
+			}
+			t.Logf("err = %v", err)
+		})
+	}
+
+	var s sample
+	err := StrictComplete(strings.NewReader(`{"a":`), &s)
+	if err == nil || errors.Is(err, ErrTrailingData) {
+		t.Errorf("should cause a syntax error, but is %v", err)
+	}
+}
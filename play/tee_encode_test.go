@@ -0,0 +1,119 @@
+package play
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"errors"
+	"io"
+	"testing"
+)
+
+// TeeWriter writes to w and mirrors to log the bytes w accepted.
+//
+// An error from w is returned by Write as is. An error from log is not:
+// it detaches log, so later writes go to w only, and is reported by LogErr. Thus logging never breaks the output.
+type TeeWriter struct {
+	w      io.Writer
+	log    io.Writer
+	logErr error
+}
+
+func NewTeeWriter(w, log io.Writer) *TeeWriter {
+	return &TeeWriter{w: w, log: log}
+}
+
+func (t *TeeWriter) Write(b []byte) (int, error) {
+	n, err := t.w.Write(b)
+	if t.log != nil && n > 0 {
+		if _, logErr := t.log.Write(b[:n]); logErr != nil {
+			t.log, t.logErr = nil, logErr
+		}
+	}
+	return n, err
+}
+
+// LogErr returns the error which detached log, if any.
+func (t *TeeWriter) LogErr() error {
+	return t.logErr
+}
+
+// Flush flushes w and log if they implement interface{ Flush() error }.
+func (t *TeeWriter) Flush() error {
+	var errs []error
+	for _, w := range []io.Writer{t.w, t.log} {
+		if f, ok := w.(interface{ Flush() error }); ok {
+			errs = append(errs, f.Flush())
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Close flushes, then closes w and log if they implement io.Closer.
+func (t *TeeWriter) Close() error {
+	errs := []error{t.Flush()}
+	for _, w := range []io.Writer{t.w, t.log} {
+		if c, ok := w.(io.Closer); ok {
+			errs = append(errs, c.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// TeeEncode returns an encoder writing to w whose output is mirrored byte-for-byte to log,
+// e.g. for request/response logging without marshaling twice,
+// along with the TeeWriter to flush or close both sides.
+//
+// It takes the destination writer instead of an existing *jsontext.Encoder, since an encoder does not expose its writer.
+func TeeEncode(w, log io.Writer, opts ...jsontext.Options) (*jsontext.Encoder, *TeeWriter) {
+	tw := NewTeeWriter(w, log)
+	return jsontext.NewEncoder(tw, opts...), tw
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write(b []byte) (int, error) {
+	return 0, errors.New("failing")
+}
+
+func TestTeeEncode(t *testing.T) {
+	type sample struct {
+		Foo string         `json:"foo"`
+		Bar []int          `json:"bar"`
+		Baz map[string]any `json:"baz"`
+	}
+	values := []sample{
+		{Foo: "a", Bar: []int{1, 2}},
+		{Foo: "b", Baz: map[string]any{"x": "<y>"}},
+	}
+
+	var out, log bytes.Buffer
+	bw := bufio.NewWriter(&out)
+	enc, tw := TeeEncode(bw, &log, jsontext.Multiline(true))
+	for _, v := range values {
+		if err := json.MarshalEncode(enc, v); err != nil {
+			panic(err)
+		}
+	}
+	if out.Len() != 0 {
+		t.Errorf("out should be still buffered, but is %q", out.String())
+	}
+	if err := tw.Flush(); err != nil {
+		panic(err)
+	}
+	if out.Len() == 0 || !bytes.Equal(out.Bytes(), log.Bytes()) {
+		t.Errorf("not equal: expected(%s) != actual(%s)", out.String(), log.String())
+	}
+
+	var out2 bytes.Buffer
+	enc, tw = TeeEncode(&out2, failingWriter{})
+	for _, v := range values {
+		if err := json.MarshalEncode(enc, v); err != nil {
+			t.Fatalf("log failure should not fail encoding: %v", err)
+		}
+	}
+	if tw.LogErr() == nil || out2.Len() == 0 {
+		t.Errorf("log error should be reported: err = %v, out = %s", tw.LogErr(), out2.String())
+	}
+}
@@ -0,0 +1,71 @@
+package play
+
+import (
+	"encoding/json/jsontext"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+// WithTokenObserver calls fn for every token read from the source decoder,
+// with the kind of the token and the stack pointer right after it is read.
+//
+// Since fn observes the source decoder rather than the arshaler,
+// tokens consumed by arshalers through ReadValue or SkipValue are observed all the same.
+func WithTokenObserver(fn func(kind jsontext.Kind, ptr jsontext.Pointer)) DecodeOption {
+	return func(c *decodeConfig) {
+		c.hooks = append(c.hooks, func(dec *jsontext.Decoder, tok jsontext.Token, emit func(jsontext.Token) error) error {
+			fn(tok.Kind(), dec.StackPointer())
+			return emit(tok)
+		})
+	}
+}
+
+func TestDecodeOption_TokenObserver(t *testing.T) {
+	type sample struct {
+		Foo string
+		Bar Either[[]int, string]
+		Baz Option[map[string]bool]
+	}
+
+	var observed []string
+	var s sample
+	err := UnmarshalWith(
+		[]byte(`{"Foo":"foo","Bar":[1,2],"Baz":{"qux":true},"Unknown":{"a":null}}`),
+		&s,
+		WithTokenObserver(func(kind jsontext.Kind, ptr jsontext.Pointer) {
+			observed = append(observed, fmt.Sprintf("%s %s", kind, ptr))
+		}),
+	)
+	if err != nil {
+		panic(err)
+	}
+	if s.Foo != "foo" || !slices.Equal(s.Bar.Left(), []int{1, 2}) || !s.Baz.Value()["qux"] {
+		t.Errorf("incorrect: %#v", s)
+	}
+
+	expected := []string{
+		"{ ",
+		"string /Foo",
+		"string /Foo",
+		"string /Bar",
+		"[ /Bar",
+		"number /Bar/0",
+		"number /Bar/1",
+		"] /Bar",
+		"string /Baz",
+		"{ /Baz",
+		"string /Baz/qux",
+		"true /Baz/qux",
+		"} /Baz",
+		"string /Unknown",
+		"{ /Unknown",
+		"string /Unknown/a",
+		"null /Unknown/a",
+		"} /Unknown",
+		"} ",
+	}
+	if !slices.Equal(observed, expected) {
+		t.Errorf("not equal:\nexpected(%q)\n!=\nactual(%q)", expected, observed)
+	}
+}
@@ -0,0 +1,70 @@
+package play
+
+import (
+	"encoding/json/jsontext"
+	"strings"
+	"testing"
+	"unicode"
+)
+
+// WithTrimStrings trims leading and trailing unicode white spaces from every decoded string value.
+// Object names are kept as is.
+func WithTrimStrings() DecodeOption {
+	return WithTrimStringsFunc(unicode.IsSpace, false)
+}
+
+// WithTrimStringsFunc trims leading and trailing runes satisfying isTrimmed from every decoded string value,
+// and from object names also if keys is true.
+func WithTrimStringsFunc(isTrimmed func(r rune) bool, keys bool) DecodeOption {
+	return func(c *decodeConfig) {
+		c.hooks = append(c.hooks, func(dec *jsontext.Decoder, tok jsontext.Token, emit func(jsontext.Token) error) error {
+			if tok.Kind() != '"' {
+				return emit(tok)
+			}
+			if !keys && justReadName(dec) {
+				return emit(tok)
+			}
+			return emit(jsontext.String(strings.TrimFunc(tok.String(), isTrimmed)))
+		})
+	}
+}
+
+func TestDecodeOption_TrimStrings(t *testing.T) {
+	type sample struct {
+		Name string   `json:"name"`
+		Tags []string `json:"tags"`
+		Raw  map[string]string
+	}
+
+	input := []byte(`{"name":"  bob  ","tags":["\tfoo\n"," bar"],"Raw":{" baz ":"　qux　"}}`)
+
+	var s sample
+	err := UnmarshalWith(input, &s, WithTrimStrings())
+	if err != nil {
+		panic(err)
+	}
+	if s.Name != "bob" {
+		t.Errorf("not equal: expected(%q) != actual(%q)", "bob", s.Name)
+	}
+	if len(s.Tags) != 2 || s.Tags[0] != "foo" || s.Tags[1] != "bar" {
+		t.Errorf("incorrect: %#v", s.Tags)
+	}
+	if v, ok := s.Raw[" baz "]; !ok || v != "qux" {
+		t.Errorf("incorrect: %#v", s.Raw)
+	}
+
+	s = sample{}
+	err = UnmarshalWith(input, &s, WithTrimStringsFunc(func(r rune) bool { return r == ' ' }, true))
+	if err != nil {
+		panic(err)
+	}
+	if s.Name != "bob" {
+		t.Errorf("not equal: expected(%q) != actual(%q)", "bob", s.Name)
+	}
+	if len(s.Tags) != 2 || s.Tags[0] != "\tfoo\n" || s.Tags[1] != "bar" {
+		t.Errorf("incorrect: %#v", s.Tags)
+	}
+	if v, ok := s.Raw["baz"]; !ok || v != "　qux　" {
+		t.Errorf("incorrect: %#v", s.Raw)
+	}
+}
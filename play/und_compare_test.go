@@ -0,0 +1,52 @@
+package play
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+// Equal reports whether u and other are in the same state, and for Defined, whether eq reports their values equal.
+// eq lets V be non-comparable, e.g. a slice or a map.
+func (u Und[V]) Equal(other Und[V], eq func(a, b V) bool) bool {
+	return u.opt.Equal(other.opt, func(a, b Option[V]) bool { return a.Equal(b, eq) })
+}
+
+// EqualUnd is Und.Equal for a comparable V, comparing values by ==.
+func EqualUnd[V comparable](a, b Und[V]) bool {
+	return a.Equal(b, func(a, b V) bool { return a == b })
+}
+
+func TestUndEqual(t *testing.T) {
+	states := []struct {
+		name string
+		u    Und[[]int]
+	}{
+		{"undefined", Undefined[[]int]()},
+		{"null", Null[[]int]()},
+		{"defined", Defined([]int{1, 2})},
+	}
+	for i, a := range states {
+		for j, b := range states {
+			t.Run(fmt.Sprintf("%s-%s", a.name, b.name), func(t *testing.T) {
+				expected := i == j
+				if got := a.u.Equal(b.u, slices.Equal); got != expected {
+					t.Errorf("not equal: expected(%t) != actual(%t)", expected, got)
+				}
+			})
+		}
+	}
+
+	if Defined([]int{1, 2}).Equal(Defined([]int{2, 1}), slices.Equal) {
+		t.Errorf("should not be equal for different values")
+	}
+	// nil and empty are equal by slices.Equal, while the states stay distinguished.
+	if !Defined([]int(nil)).Equal(Defined([]int{}), slices.Equal) || Defined([]int(nil)).Equal(Null[[]int](), slices.Equal) {
+		t.Errorf("incorrect for nil values")
+	}
+
+	if !EqualUnd(Undefined[string](), Undefined[string]()) || EqualUnd(Null[string](), Defined("")) ||
+		!EqualUnd(Defined("a"), Defined("a")) || EqualUnd(Defined("a"), Defined("b")) {
+		t.Errorf("incorrect EqualUnd")
+	}
+}
@@ -0,0 +1,71 @@
+package play
+
+import "testing"
+
+// ToOption returns Some of the value if u is defined, or None if u is undefined or null.
+func (u Und[V]) ToOption() Option[V] {
+	if !u.IsDefined() {
+		return None[V]()
+	}
+	return Some(u.Value())
+}
+
+// NullAsNone maps null to None, and undefined to Some of the zero value.
+func (u Und[V]) NullAsNone() Option[V] {
+	if u.IsNull() {
+		return None[V]()
+	}
+	return Some(u.Value())
+}
+
+// UndefinedAsNone maps undefined to None, and null to Some of the zero value.
+// It follows PATCH semantics: ApplyOption(current, u.UndefinedAsNone()) is the same as ApplyUnd(current, u).
+func (u Und[V]) UndefinedAsNone() Option[V] {
+	if u.IsUndefined() {
+		return None[V]()
+	}
+	return Some(u.Value())
+}
+
+// OptionToUnd returns Null for None, or Defined of the value for Some.
+func OptionToUnd[V any](o Option[V]) Und[V] {
+	if o.IsNone() {
+		return Null[V]()
+	}
+	return Defined(o.Value())
+}
+
+func TestUndOption(t *testing.T) {
+	type testCase struct {
+		name            string
+		in              Und[int]
+		toOption        Option[int]
+		nullAsNone      Option[int]
+		undefinedAsNone Option[int]
+		roundTrip       Und[int]
+	}
+	for _, tc := range []testCase{
+		{"undefined", Undefined[int](), None[int](), Some(0), None[int](), Null[int]()},
+		{"null", Null[int](), None[int](), None[int](), Some(0), Null[int]()},
+		{"defined", Defined(5), Some(5), Some(5), Some(5), Defined(5)},
+		{"defined zero", Defined(0), Some(0), Some(0), Some(0), Defined(0)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if o := tc.in.ToOption(); o != tc.toOption {
+				t.Errorf("ToOption: not equal: expected(%#v) != actual(%#v)", tc.toOption, o)
+			}
+			if o := tc.in.NullAsNone(); o != tc.nullAsNone {
+				t.Errorf("NullAsNone: not equal: expected(%#v) != actual(%#v)", tc.nullAsNone, o)
+			}
+			if o := tc.in.UndefinedAsNone(); o != tc.undefinedAsNone {
+				t.Errorf("UndefinedAsNone: not equal: expected(%#v) != actual(%#v)", tc.undefinedAsNone, o)
+			}
+			if u := OptionToUnd(tc.in.ToOption()); u != tc.roundTrip {
+				t.Errorf("OptionToUnd: not equal: expected(%#v) != actual(%#v)", tc.roundTrip, u)
+			}
+			if a, b := ApplyOption(7, tc.in.UndefinedAsNone()), ApplyUnd(7, tc.in); a != b {
+				t.Errorf("UndefinedAsNone should follow ApplyUnd: %d != %d", a, b)
+			}
+		})
+	}
+}
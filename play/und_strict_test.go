@@ -0,0 +1,100 @@
+package play
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var ErrUndefinedNotOmitted = errors.New("undefined Und not omitted")
+
+type undefinable interface {
+	IsUndefined() bool
+}
+
+// UndStrict returns options that fail marshaling an undefined Und which reaches the encoder,
+// instead of silently writing null for it.
+// An undefined Und reaches the encoder where it can not be omitted:
+// an array element, a map value, a struct field without omitzero or the top-level value.
+// The error wraps ErrUndefinedNotOmitted and names the pointer of the value.
+func UndStrict() json.Options {
+	return json.WithMarshalers(json.MarshalToFunc(func(enc *jsontext.Encoder, u undefinable) error {
+		if !u.IsUndefined() {
+			return errors.ErrUnsupported
+		}
+		ptr, context := nextValuePointer(enc)
+		return fmt.Errorf("%w: %s at %q", ErrUndefinedNotOmitted, context, ptr)
+	}))
+}
+
+// nextValuePointer returns the pointer of the value enc is about to write and a description of where it is.
+func nextValuePointer(enc *jsontext.Encoder) (jsontext.Pointer, string) {
+	kind, length := enc.StackIndex(enc.StackDepth())
+	switch kind {
+	case '[':
+		// StackPointer points to the last written element, or to the array itself if none.
+		parent := enc.StackPointer()
+		if length > 0 {
+			parent = parent[:len(parent)-len(parent.LastToken())-1]
+		}
+		return parent.AppendToken(strconv.FormatInt(length, 10)), "array element"
+	case '{':
+		// The name is already written.
+		return enc.StackPointer(), "object member"
+	default:
+		return "", "top-level value"
+	}
+}
+
+func TestUndStrict(t *testing.T) {
+	type sample struct {
+		Omitted Und[int]            `json:"omitted,omitzero"`
+		List    []Und[int]          `json:"list"`
+		Map     map[string]Und[int] `json:"map"`
+	}
+
+	s := sample{
+		List: []Und[int]{Defined(1), Null[int]()},
+		Map:  map[string]Und[int]{"a": Defined(2)},
+	}
+	bin, err := json.Marshal(s, UndStrict())
+	if err != nil {
+		panic(err)
+	}
+	if expected := `{"list":[1,null],"map":{"a":2}}`; string(bin) != expected {
+		t.Errorf("not equal: expected(%s) != actual(%s)", expected, string(bin))
+	}
+
+	type testCase struct {
+		name     string
+		in       any
+		expected string
+	}
+	for _, tc := range []testCase{
+		{"array element", sample{List: []Und[int]{Defined(1), Null[int](), Undefined[int]()}}, `array element at "/list/2"`},
+		{"first array element", []Und[int]{Undefined[int]()}, `array element at "/0"`},
+		{"map value", sample{Map: map[string]Und[int]{"b": Undefined[int]()}}, `object member at "/map/b"`},
+		{"field without omitzero", struct{ U Und[int] }{}, `object member at "/U"`},
+		{"top-level", Undefined[int](), `top-level value at ""`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := json.Marshal(tc.in)
+			if err != nil {
+				t.Fatalf("should not fail without strict mode: %v", err)
+			}
+			_, err = json.Marshal(tc.in, UndStrict())
+			if !errors.Is(err, ErrUndefinedNotOmitted) {
+				t.Fatalf("should be ErrUndefinedNotOmitted, but is %v", err)
+			}
+			if !strings.Contains(err.Error(), tc.expected) {
+				t.Errorf("error should contain %q, but is %v", tc.expected, err)
+			}
+			t.Logf("err = %v", err)
+			// und_strict_test.go:96: err = json: cannot marshal from Go play.undefinable within "/list/2": undefined Und not omitted: array element at "/list/2"
+		})
+	}
+}
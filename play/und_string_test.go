@@ -0,0 +1,67 @@
+package play
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+var (
+	_ fmt.Stringer   = Und[any]{}
+	_ fmt.GoStringer = Und[any]{}
+)
+
+// String returns "Defined(<v>)" formatting the value with %v, "Null" or "Undefined".
+func (u Und[V]) String() string {
+	switch {
+	case u.IsUndefined():
+		return "Undefined"
+	case u.IsNull():
+		return "Null"
+	}
+	return fmt.Sprintf("Defined(%v)", u.Value())
+}
+
+// GoString returns u as a Go expression constructing it, e.g. play.Defined[int](3) or play.Null[int]().
+func (u Und[V]) GoString() string {
+	typ := reflect.TypeFor[V]().String()
+	switch {
+	case u.IsUndefined():
+		return fmt.Sprintf("play.Undefined[%s]()", typ)
+	case u.IsNull():
+		return fmt.Sprintf("play.Null[%s]()", typ)
+	}
+	return fmt.Sprintf("play.Defined[%s](%#v)", typ, u.Value())
+}
+
+func TestUndString(t *testing.T) {
+	type testCase struct {
+		in       any
+		str      string
+		goString string
+	}
+	for _, tc := range []testCase{
+		{Defined(3), "Defined(3)", "play.Defined[int](3)"},
+		{Null[int](), "Null", "play.Null[int]()"},
+		{Undefined[int](), "Undefined", "play.Undefined[int]()"},
+		{Defined("foo"), "Defined(foo)", `play.Defined[string]("foo")`},
+		{Defined(Some(1)), "Defined(Some(1))", "play.Defined[play.Option[int]](play.Some[int](1))"},
+		{
+			[]Und[int]{Defined(1), Null[int](), Undefined[int]()},
+			"[Defined(1) Null Undefined]",
+			"[]play.Und[int]{play.Defined[int](1), play.Null[int](), play.Undefined[int]()}",
+		},
+		{
+			struct{ U Und[[]string] }{Defined([]string{"a"})},
+			"{Defined([a])}",
+			`struct { U play.Und[[]string] }{U:play.Defined[[]string]([]string{"a"})}`,
+		},
+	} {
+		if s := fmt.Sprintf("%v", tc.in); s != tc.str {
+			t.Errorf("not equal: expected(%s) != actual(%s)", tc.str, s)
+		}
+		if s := fmt.Sprintf("%#v", tc.in); s != tc.goString {
+			t.Errorf("not equal: expected(%s) != actual(%s)", tc.goString, s)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package play
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// Then returns fn applied to the value of u if u is defined, otherwise u as is,
+// so undefined and null pass through a chain keeping their state.
+func (u Und[V]) Then(fn func(V) Und[V]) Und[V] {
+	if !u.IsDefined() {
+		return u
+	}
+	return fn(u.Value())
+}
+
+// OrDefault returns the value of u if u is defined, or v if u is undefined or null.
+func (u Und[V]) OrDefault(v V) V {
+	if !u.IsDefined() {
+		return v
+	}
+	return u.Value()
+}
+
+// MapUnd maps the value of u with f if u is defined, keeping undefined and null as they are.
+func MapUnd[V, U any](u Und[V], f func(V) U) Und[U] {
+	switch {
+	case u.IsUndefined():
+		return Undefined[U]()
+	case u.IsNull():
+		return Null[U]()
+	}
+	return Defined(f(u.Value()))
+}
+
+// Or returns u if u is defined, otherwise other.
+func (u Und[V]) Or(other Und[V]) Und[V] {
+	if u.IsDefined() {
+		return u
+	}
+	return other
+}
+
+func TestUndThen(t *testing.T) {
+	trim := func(s string) Und[string] { return Defined(strings.TrimSpace(s)) }
+	// an empty string clears the field.
+	emptyAsNull := func(s string) Und[string] {
+		if s == "" {
+			return Null[string]()
+		}
+		return Defined(s)
+	}
+
+	type testCase struct {
+		name     string
+		in       Und[string]
+		expected Und[string]
+		orDef    string
+	}
+	for _, tc := range []testCase{
+		{"defined", Defined("  foo "), Defined("foo"), "foo"},
+		{"defined to null", Defined("   "), Null[string](), "default"},
+		{"null", Null[string](), Null[string](), "default"},
+		{"undefined", Undefined[string](), Undefined[string](), "default"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			called := false
+			u := tc.in.Then(trim).Then(emptyAsNull).Then(func(s string) Und[string] { called = true; return Defined(s) })
+			if u != tc.expected {
+				t.Errorf("not equal: expected(%#v) != actual(%#v)", tc.expected, u)
+			}
+			if called != tc.expected.IsDefined() {
+				t.Errorf("fn should be called only for defined: called = %t", called)
+			}
+			if got := u.OrDefault("default"); got != tc.orDef {
+				t.Errorf("not equal: expected(%s) != actual(%s)", tc.orDef, got)
+			}
+		})
+	}
+}
+
+func TestUndMap(t *testing.T) {
+	calls := 0
+	itoa := func(i int) string { calls++; return strconv.Itoa(i) }
+
+	type testCase struct {
+		in       Und[int]
+		expected Und[string]
+	}
+	for _, tc := range []testCase{
+		{Undefined[int](), Undefined[string]()},
+		{Null[int](), Null[string]()},
+		{Defined(5), Defined("5")},
+	} {
+		if u := MapUnd(tc.in, itoa); u != tc.expected {
+			t.Errorf("not equal: expected(%#v) != actual(%#v)", tc.expected, u)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("f should be called only for defined: calls = %d", calls)
+	}
+}
+
+func TestUndOr(t *testing.T) {
+	undefined, null, one, two := Undefined[int](), Null[int](), Defined(1), Defined(2)
+
+	type testCase struct {
+		u, other Und[int]
+		expected Und[int]
+	}
+	for _, tc := range []testCase{
+		{undefined, undefined, undefined},
+		{undefined, null, null},
+		{undefined, two, two},
+		{null, undefined, undefined},
+		{null, null, null},
+		{null, two, two},
+		{one, undefined, one},
+		{one, null, one},
+		{one, two, one},
+	} {
+		if got := tc.u.Or(tc.other); got != tc.expected {
+			t.Errorf("%#v.Or(%#v): not equal: expected(%#v) != actual(%#v)", tc.u, tc.other, tc.expected, got)
+		}
+	}
+}
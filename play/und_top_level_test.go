@@ -0,0 +1,74 @@
+package play
+
+import (
+	"bytes"
+	"encoding/json/v2"
+	"testing"
+)
+
+// MarshalUnd marshals a standalone u, encoding undefined as empty output rather than null,
+// so that all three states survive a round trip through UnmarshalUnd.
+func MarshalUnd[V any](u Und[V], opts ...json.Options) ([]byte, error) {
+	if u.IsUndefined() {
+		return []byte{}, nil
+	}
+	return json.Marshal(u, opts...)
+}
+
+// UnmarshalUnd is the counterpart of MarshalUnd: empty or whitespace-only data decodes to undefined.
+func UnmarshalUnd[V any](data []byte, opts ...json.Options) (Und[V], error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return Undefined[V](), nil
+	}
+	var u Und[V]
+	err := json.Unmarshal(data, &u, opts...)
+	return u, err
+}
+
+func TestMarshalUnd(t *testing.T) {
+	type testCase struct {
+		name     string
+		in       Und[int]
+		expected string
+	}
+	for _, tc := range []testCase{
+		{"undefined", Undefined[int](), ""},
+		{"null", Null[int](), "null"},
+		{"defined", Defined(3), "3"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			bin, err := MarshalUnd(tc.in)
+			if err != nil {
+				panic(err)
+			}
+			if string(bin) != tc.expected {
+				t.Errorf("not equal: expected(%s) != actual(%s)", tc.expected, string(bin))
+			}
+			u, err := UnmarshalUnd[int](bin)
+			if err != nil {
+				panic(err)
+			}
+			if u != tc.in {
+				t.Errorf("not round-tripped: expected(%#v) != actual(%#v)", tc.in, u)
+			}
+		})
+	}
+
+	// map values: undefined is absent only with WithMapUnd; without it, it is indistinguishable from null.
+	m := map[string]Und[int]{"u": Undefined[int](), "n": Null[int](), "d": Defined(1)}
+	for _, c := range []struct {
+		opts     []json.Options
+		expected string
+	}{
+		{[]json.Options{json.Deterministic(true)}, `{"d":1,"n":null,"u":null}`},
+		{[]json.Options{json.Deterministic(true), WithMapUnd[string, int]()}, `{"d":1,"n":null}`},
+	} {
+		bin, err := json.Marshal(m, c.opts...)
+		if err != nil {
+			panic(err)
+		}
+		if string(bin) != c.expected {
+			t.Errorf("not equal: expected(%s) != actual(%s)", c.expected, string(bin))
+		}
+	}
+}
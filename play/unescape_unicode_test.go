@@ -0,0 +1,136 @@
+package play
+
+import (
+	"bytes"
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// UnescapeUnicode reads a value from dec and writes it to enc
+// with \uXXXX escapes in strings and names converted to literal UTF-8 characters.
+// Escapes required by JSON, i.e. quotes, backslashes and control characters, are kept.
+func UnescapeUnicode(dec *jsontext.Decoder, enc *jsontext.Encoder) error {
+	return rewriteStrings(dec, enc, func(s string) (jsontext.Token, jsontext.Value, error) {
+		// tok.String is already unescaped; the encoder escapes only what is required.
+		return jsontext.String(s), nil, nil
+	})
+}
+
+// EscapeNonASCII reads a value from dec and writes it to enc
+// with every non-ASCII character in strings and names escaped as \uXXXX, or a surrogate pair of them,
+// so that the output is ASCII-only.
+//
+// enc must be created with jsontext.PreserveRawStrings(true), otherwise it would unescape them again.
+func EscapeNonASCII(dec *jsontext.Decoder, enc *jsontext.Encoder) error {
+	if v, _ := json.GetOption(enc.Options(), jsontext.PreserveRawStrings); !v {
+		return errors.New("EscapeNonASCII: enc must be created with jsontext.PreserveRawStrings(true)")
+	}
+	var buf []byte
+	return rewriteStrings(dec, enc, func(s string) (jsontext.Token, jsontext.Value, error) {
+		quoted, err := jsontext.AppendQuote(buf[:0], s)
+		if err != nil {
+			return jsontext.Token{}, nil, err
+		}
+		buf = quoted
+		return jsontext.Token{}, escapeNonASCII(quoted), nil
+	})
+}
+
+func escapeNonASCII(quoted []byte) jsontext.Value {
+	out := make([]byte, 0, len(quoted))
+	for len(quoted) > 0 {
+		r, size := utf8.DecodeRune(quoted)
+		quoted = quoted[size:]
+		switch {
+		case r < utf8.RuneSelf:
+			out = append(out, byte(r))
+		case r > 0xffff:
+			r1, r2 := utf16.EncodeRune(r)
+			out = fmt.Appendf(out, `\u%04x\u%04x`, r1, r2)
+		default:
+			out = fmt.Appendf(out, `\u%04x`, r)
+		}
+	}
+	return out
+}
+
+// rewriteStrings copies a value from dec to enc, replacing each string token, including names,
+// with either the token or the raw value returned by fn.
+func rewriteStrings(dec *jsontext.Decoder, enc *jsontext.Encoder, fn func(s string) (jsontext.Token, jsontext.Value, error)) error {
+	depth := dec.StackDepth()
+	for {
+		tok, err := dec.ReadToken()
+		if err != nil {
+			return err
+		}
+		if tok.Kind() == '"' {
+			var val jsontext.Value
+			tok, val, err = fn(tok.String())
+			if err != nil {
+				return err
+			}
+			if val != nil {
+				err = enc.WriteValue(val)
+			} else {
+				err = enc.WriteToken(tok)
+			}
+		} else {
+			err = enc.WriteToken(tok)
+		}
+		if err != nil {
+			return err
+		}
+		if dec.StackDepth() == depth {
+			return nil
+		}
+	}
+}
+
+func TestUnescapeUnicode(t *testing.T) {
+	type testCase struct {
+		in        string
+		unescaped string
+		escaped   string
+	}
+	for _, tc := range []testCase{
+		{`"caf\u00e9"`, `"café"`, `"caf\u00e9"`},
+		{`{"na\u00efve":["\ud83d\ude00","plain"]}`, `{"naïve":["😀","plain"]}`, `{"na\u00efve":["\ud83d\ude00","plain"]}`},
+		// required escapes are kept in both.
+		{`"\u0022q\u005c\u0001\n\u3042"`, `"\"q\\\u0001\nあ"`, `"\"q\\\u0001\n\u3042"`},
+		{`[1,true,null]`, `[1,true,null]`, `[1,true,null]`},
+	} {
+		t.Run(tc.in, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := UnescapeUnicode(jsontext.NewDecoder(strings.NewReader(tc.in)), jsontext.NewEncoder(&buf))
+			if err != nil {
+				panic(err)
+			}
+			if got := strings.TrimSpace(buf.String()); got != tc.unescaped {
+				t.Errorf("not equal: expected(%s) != actual(%s)", tc.unescaped, got)
+			}
+
+			buf.Reset()
+			err = EscapeNonASCII(
+				jsontext.NewDecoder(strings.NewReader(tc.unescaped)),
+				jsontext.NewEncoder(&buf, jsontext.PreserveRawStrings(true)),
+			)
+			if err != nil {
+				panic(err)
+			}
+			if got := strings.TrimSpace(buf.String()); got != tc.escaped {
+				t.Errorf("not equal: expected(%s) != actual(%s)", tc.escaped, got)
+			}
+		})
+	}
+
+	err := EscapeNonASCII(jsontext.NewDecoder(strings.NewReader(`"é"`)), jsontext.NewEncoder(&bytes.Buffer{}))
+	if err == nil {
+		t.Errorf("should fail without PreserveRawStrings")
+	}
+}
@@ -0,0 +1,82 @@
+package play
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json/jsontext"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// canonicalHash returns the hash of the RFC 8785 canonical form of v.
+// v itself is left untouched.
+func canonicalHash(v jsontext.Value) ([sha256.Size]byte, error) {
+	c := v.Clone()
+	if err := c.Canonicalize(); err != nil {
+		return [sha256.Size]byte{}, err
+	}
+	return sha256.Sum256(c), nil
+}
+
+// UniqueValues reads an array from dec and writes to enc an array containing only the first occurrence of
+// each structurally unique element.
+// Elements are compared by their canonical form, so that object member order, whitespace and
+// number representation do not matter.
+// Only hashes of seen elements are kept, not elements themselves.
+func UniqueValues(dec *jsontext.Decoder, enc *jsontext.Encoder) error {
+	if dec.PeekKind() != '[' {
+		return fmt.Errorf("unique values: expected array but got %q", dec.PeekKind().String())
+	}
+	if _, err := dec.ReadToken(); err != nil {
+		return err
+	}
+	if err := enc.WriteToken(jsontext.BeginArray); err != nil {
+		return err
+	}
+	seen := make(map[[sha256.Size]byte]struct{})
+	for dec.PeekKind() != ']' {
+		val, err := dec.ReadValue()
+		if err != nil {
+			return err
+		}
+		h, err := canonicalHash(val)
+		if err != nil {
+			return err
+		}
+		if _, ok := seen[h]; ok {
+			continue
+		}
+		seen[h] = struct{}{}
+		if err := enc.WriteValue(val); err != nil {
+			return err
+		}
+	}
+	if _, err := dec.ReadToken(); err != nil {
+		return err
+	}
+	return enc.WriteToken(jsontext.EndArray)
+}
+
+func TestUniqueValues(t *testing.T) {
+	const input = `[
+    {"a":1,"b":2},
+    {"b":2,"a":1},
+    {"b":2, "a":1.0},
+    {"a":1,"b":[1,2]},
+    {"a":1,"b":[2,1]},
+    "foo",
+    "foo",
+    null,
+    null
+]`
+	var buf bytes.Buffer
+	err := UniqueValues(jsontext.NewDecoder(strings.NewReader(input)), jsontext.NewEncoder(&buf))
+	if err != nil {
+		panic(err)
+	}
+	expected := `[{"a":1,"b":2},{"a":1,"b":[1,2]},{"a":1,"b":[2,1]},"foo",null]`
+	if got := strings.TrimSpace(buf.String()); got != expected {
+		t.Errorf("not equal:\nexpected = %s\nactual   = %s", expected, got)
+	}
+}
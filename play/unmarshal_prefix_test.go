@@ -0,0 +1,58 @@
+package play
+
+import (
+	"bytes"
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"testing"
+)
+
+// UnmarshalPrefix decodes the first top-level value in data into v and returns the unconsumed rest of data.
+// Whitespace right after the value is trimmed from rest.
+func UnmarshalPrefix(data []byte, v any, opts ...json.Options) (rest []byte, err error) {
+	dec := jsontext.NewDecoder(bytes.NewReader(data))
+	err = json.UnmarshalDecode(dec, v, opts...)
+	if err != nil {
+		return data, err
+	}
+	return bytes.TrimLeft(data[dec.InputOffset():], " \t\r\n"), nil
+}
+
+func TestUnmarshalPrefix(t *testing.T) {
+	type sample struct {
+		A int `json:"a"`
+	}
+
+	type testCase struct {
+		in       string
+		expected sample
+		rest     string
+	}
+	for _, tc := range []testCase{
+		{`{"a":1}garbage`, sample{1}, "garbage"},
+		{`{"a":2}` + " \r\n\t" + `garbage `, sample{2}, "garbage "},
+		{`{"a":3}`, sample{3}, ""},
+		{`{"a":4}{"a":5}`, sample{4}, `{"a":5}`},
+		{"{\"a\":6}\n\x00\x01\x02", sample{6}, "\x00\x01\x02"},
+	} {
+		t.Run(tc.in, func(t *testing.T) {
+			var s sample
+			rest, err := UnmarshalPrefix([]byte(tc.in), &s)
+			if err != nil {
+				panic(err)
+			}
+			if s != tc.expected {
+				t.Errorf("not equal: expected(%#v) != actual(%#v)", tc.expected, s)
+			}
+			if string(rest) != tc.rest {
+				t.Errorf("not equal: expected(%q) != actual(%q)", tc.rest, string(rest))
+			}
+		})
+	}
+
+	var s sample
+	_, err := UnmarshalPrefix([]byte(`{"a":`), &s)
+	if err == nil {
+		t.Errorf("should cause an error")
+	}
+}
@@ -0,0 +1,76 @@
+package play
+
+import (
+	"encoding/json/jsontext"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// WithMaxDepth aborts decoding with an error wrapping ErrMaxDepth
+// once objects and arrays nest deeper than n, counted as MaxDepth does.
+// The check is done on the token stream before the value reaches the unmarshaler,
+// so the unmarshaler never recurses past the limit.
+func WithMaxDepth(n int) DecodeOption {
+	return func(c *decodeConfig) {
+		c.hooks = append(c.hooks, func(dec *jsontext.Decoder, tok jsontext.Token, emit func(jsontext.Token) error) error {
+			if k := tok.Kind(); (k == '{' || k == '[') && dec.StackDepth() > n {
+				return fmt.Errorf("%w: limit = %d, at %q", ErrMaxDepth, n, dec.StackPointer())
+			}
+			return emit(tok)
+		})
+	}
+}
+
+// UnmarshalRecursive unmarshals data into v limiting nesting to maxDepth.
+// It is meant for recursive types, e.g. trees, whose depth is otherwise bounded only by the input.
+func UnmarshalRecursive[T any](data []byte, v *T, maxDepth int) error {
+	return UnmarshalWith(data, v, WithMaxDepth(maxDepth))
+}
+
+func TestUnmarshalRecursive(t *testing.T) {
+	type tree struct {
+		Value    int    `json:"value"`
+		Children []tree `json:"children,omitempty"`
+	}
+
+	// each level is an object and an array.
+	nested := func(levels int) string {
+		return strings.Repeat(`{"value":1,"children":[`, levels) + `{"value":2}` + strings.Repeat(`]}`, levels)
+	}
+
+	var tr tree
+	err := UnmarshalRecursive([]byte(`{"value":1,"children":[{"value":2},{"value":3,"children":[{"value":4}]}]}`), &tr, 8)
+	if err != nil {
+		panic(err)
+	}
+	if len(tr.Children) != 2 || tr.Children[1].Children[0].Value != 4 {
+		t.Errorf("incorrect: %#v", tr)
+	}
+
+	tr = tree{}
+	if err := UnmarshalRecursive([]byte(nested(3)), &tr, 7); err != nil {
+		panic(err)
+	}
+
+	type testCase struct {
+		name     string
+		in       string
+		maxDepth int
+	}
+	for _, tc := range []testCase{
+		{"just over", nested(3), 6},
+		{"deep", nested(5000), 64},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var tr tree
+			err := UnmarshalRecursive([]byte(tc.in), &tr, tc.maxDepth)
+			if !errors.Is(err, ErrMaxDepth) {
+				t.Fatalf("should be ErrMaxDepth, but is %v", err)
+			}
+			t.Logf("err = %v", err)
+			// unmarshal_recursive_test.go:72: err = max depth exceeded: limit = 6, at "/children/0/children/0/children/0"
+		})
+	}
+}
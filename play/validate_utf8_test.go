@@ -0,0 +1,77 @@
+package play
+
+import (
+	"encoding/json/jsontext"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+var ErrInvalidUTF8 = errors.New("invalid UTF-8")
+
+// ValidateUTF8 reads every top-level value from r and checks that every string and name is valid UTF-8,
+// including those written with escapes, e.g. a lone surrogate "\ud800".
+// The first malformed one is reported with an error wrapping ErrInvalidUTF8 naming its pointer and offset;
+// for a name, the pointer points to the member it names, with malformed bytes replaced by U+FFFD.
+// Other syntax errors are returned as is.
+func ValidateUTF8(r io.Reader) error {
+	// Let malformed strings through the decoder to report them in a dedicated error.
+	dec := jsontext.NewDecoder(r, jsontext.AllowInvalidUTF8(true), jsontext.AllowDuplicateNames(true))
+	var buf []byte
+	for {
+		if dec.PeekKind() != '"' {
+			if _, err := dec.ReadToken(); err != nil {
+				if errors.Is(err, io.EOF) {
+					return nil
+				}
+				return err
+			}
+			continue
+		}
+		raw, err := dec.ReadValue()
+		if err != nil {
+			return err
+		}
+		buf, err = jsontext.AppendUnquote(buf[:0], raw)
+		if err != nil {
+			return fmt.Errorf("%w: pointer = %q, offset = %d", ErrInvalidUTF8, dec.StackPointer(), dec.InputOffset()-int64(len(raw)))
+		}
+	}
+}
+
+func TestValidateUTF8(t *testing.T) {
+	type testCase struct {
+		name     string
+		in       string
+		expected string
+	}
+	for _, tc := range []testCase{
+		{"valid", `{"a":["ok","é😀"],"é":"😀"} ["x"] "y"`, ""},
+		{"value", "{\"a\":[\"ok\",\"b\xffc\"]}", `invalid UTF-8: pointer = "/a/1", offset = 11`},
+		{"name", "{\"ok\":1,\"n\xc3\":2}", "invalid UTF-8: pointer = \"/n\uFFFD\", offset = 8"},
+		{"lone surrogate", `{"a":1} {"b":"\ud800"}`, `invalid UTF-8: pointer = "/b", offset = 13`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateUTF8(strings.NewReader(tc.in))
+			if tc.expected == "" {
+				if err != nil {
+					panic(err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrInvalidUTF8) {
+				t.Fatalf("should be ErrInvalidUTF8, but is %v", err)
+			}
+			if err.Error() != tc.expected {
+				t.Errorf("not equal: expected(%s) != actual(%s)", tc.expected, err.Error())
+			}
+		})
+	}
+
+	err := ValidateUTF8(strings.NewReader(`{"a":}`))
+	if err == nil || errors.Is(err, ErrInvalidUTF8) {
+		t.Errorf("should be a syntax error, but is %v", err)
+	}
+}
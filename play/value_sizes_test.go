@@ -0,0 +1,82 @@
+package play
+
+import (
+	"encoding/json/jsontext"
+	"errors"
+	"io"
+	"iter"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// ValueSizes yields the byte length of each consecutive top-level value read from r,
+// excluding whitespace around it. Values are only validated, not decoded.
+//
+// Iteration stops after an error is yielded, or at the end of r.
+func ValueSizes(r io.Reader) iter.Seq2[int64, error] {
+	return func(yield func(int64, error) bool) {
+		dec := jsontext.NewDecoder(r)
+		for {
+			if dec.PeekKind() == 0 {
+				// PeekKind hides errors, including io.EOF; let SkipValue report it.
+				err := dec.SkipValue()
+				if !errors.Is(err, io.EOF) {
+					yield(0, err)
+				}
+				return
+			}
+			start := peekOffset(dec)
+			if err := dec.SkipValue(); err != nil {
+				yield(0, err)
+				return
+			}
+			if !yield(dec.InputOffset()-start, nil) {
+				return
+			}
+		}
+	}
+}
+
+func TestValueSizes(t *testing.T) {
+	records := []string{
+		`{"id":1,"name":"foo"}`,
+		`[1,2,3]`,
+		`"str"`,
+		`12345`,
+		`{"nested":{"list":[{"a":"b"},null,true]}}`,
+	}
+	input := " " + strings.Join(records, "\n  ") + "\n\n"
+
+	var (
+		sizes []int64
+		sum   int64
+	)
+	for size, err := range ValueSizes(strings.NewReader(input)) {
+		if err != nil {
+			panic(err)
+		}
+		sizes = append(sizes, size)
+		sum += size
+	}
+	var expected []int64
+	for _, r := range records {
+		expected = append(expected, int64(len(r)))
+	}
+	if !slices.Equal(sizes, expected) {
+		t.Errorf("not equal: expected(%v) != actual(%v)", expected, sizes)
+	}
+	if ws := int64(len(input) - len(strings.Join(records, ""))); sum+ws != int64(len(input)) {
+		t.Errorf("sizes should sum up to the input length without whitespace: sum = %d, input = %d", sum, len(input))
+	}
+
+	var errs []error
+	for _, err := range ValueSizes(strings.NewReader(`{"a":1} {"b":`)) {
+		errs = append(errs, err)
+	}
+	if len(errs) != 2 || errs[0] != nil || errs[1] == nil {
+		t.Errorf("should yield a size then an error, but is %v", errs)
+	}
+	t.Logf("err = %v", errs[len(errs)-1])
+	// value_sizes_test.go:80: err = jsontext: unexpected EOF within "/b" after offset 13
+}
@@ -0,0 +1,222 @@
+package play
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+var (
+	_ json.MarshalerTo     = Variant[any]{}
+	_ json.UnmarshalerFrom = (*Variant[any])(nil)
+)
+
+var ErrNoVariant = errors.New("no variant matched")
+
+type variantEntry struct {
+	typ  reflect.Type
+	hint jsontext.Kind
+}
+
+type variantRegistry struct {
+	mu      sync.RWMutex
+	entries []variantEntry
+	// byKind memoizes entries whose hint matches the kind, in registration order.
+	byKind map[jsontext.Kind][]variantEntry
+}
+
+// variantRegistries holds *variantRegistry keyed by reflect.Type of U.
+var variantRegistries sync.Map
+
+func variantRegistryFor[U any]() *variantRegistry {
+	r, _ := variantRegistries.LoadOrStore(reflect.TypeFor[U](), &variantRegistry{})
+	return r.(*variantRegistry)
+}
+
+// RegisterOrdered registers T as a variant of Variant[U], where U is any type identifying the union.
+// kindHint is the kind of JSON values T is decoded from, or 0 if T may accept any kind.
+// 't' and 'f' are the same as a hint.
+//
+// Variants are tried in registration order among those whose hint matches the next kind.
+// Registering T again replaces its hint, keeping its position.
+func RegisterOrdered[U, T any](kindHint jsontext.Kind) {
+	r := variantRegistryFor[U]()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entry := variantEntry{typ: reflect.TypeFor[T](), hint: normalizeKind(kindHint)}
+	if i := slices.IndexFunc(r.entries, func(e variantEntry) bool { return e.typ == entry.typ }); i >= 0 {
+		r.entries[i] = entry
+	} else {
+		r.entries = append(r.entries, entry)
+	}
+	r.byKind = make(map[jsontext.Kind][]variantEntry)
+	for _, k := range []jsontext.Kind{'n', 't', '"', '0', '{', '['} {
+		for _, e := range r.entries {
+			if e.hint == 0 || e.hint == k {
+				r.byKind[k] = append(r.byKind[k], e)
+			}
+		}
+	}
+}
+
+func normalizeKind(k jsontext.Kind) jsontext.Kind {
+	if k == 'f' {
+		return 't'
+	}
+	return k
+}
+
+// Variant holds a value of one of types registered by RegisterOrdered for U.
+// Unlike Either, it first narrows candidates down by the next kind,
+// then decodes into the first candidate that succeeds.
+// A single candidate is decoded directly from the decoder without buffering.
+type Variant[U any] struct {
+	v any
+}
+
+// Value returns the held value, or nil if none.
+func (v Variant[U]) Value() any {
+	return v.v
+}
+
+// Type returns the type of the matched variant, or nil if none.
+func (v Variant[U]) Type() reflect.Type {
+	return reflect.TypeOf(v.v)
+}
+
+// VariantAs returns the value of v as T, reporting whether v holds T.
+func VariantAs[T, U any](v Variant[U]) (T, bool) {
+	t, ok := v.v.(T)
+	return t, ok
+}
+
+func (v Variant[U]) MarshalJSONTo(enc *jsontext.Encoder) error {
+	return json.MarshalEncode(enc, v.v)
+}
+
+func (v *Variant[U]) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	kind := normalizeKind(dec.PeekKind())
+	r := variantRegistryFor[U]()
+	r.mu.RLock()
+	candidates := r.byKind[kind]
+	r.mu.RUnlock()
+
+	switch len(candidates) {
+	case 0:
+		if err := dec.SkipValue(); err != nil {
+			return err
+		}
+		return fmt.Errorf("%w: no variant accepts %s", ErrNoVariant, kind)
+	case 1:
+		rv := reflect.New(candidates[0].typ)
+		if err := json.UnmarshalDecode(dec, rv.Interface()); err != nil {
+			return err
+		}
+		v.v = rv.Elem().Interface()
+		return nil
+	}
+
+	val, err := dec.ReadValue()
+	if err != nil {
+		return err
+	}
+	var errs []string
+	for _, c := range candidates {
+		rv := reflect.New(c.typ)
+		err := json.Unmarshal(val, rv.Interface(), dec.Options())
+		if err == nil {
+			v.v = rv.Elem().Interface()
+			return nil
+		}
+		errs = append(errs, fmt.Sprintf("%s = (%v)", c.typ, err))
+	}
+	return fmt.Errorf("%w: %s", ErrNoVariant, strings.Join(errs, ", "))
+}
+
+func TestVariant(t *testing.T) {
+	type point struct {
+		X, Y int
+	}
+	type named struct {
+		Name string
+	}
+	type shapes struct{}
+	RegisterOrdered[shapes, string]('"')
+	RegisterOrdered[shapes, float64]('0')
+	RegisterOrdered[shapes, point]('{')
+	RegisterOrdered[shapes, []int]('[')
+
+	type testCase struct {
+		in       string
+		expected any
+	}
+	for _, tc := range []testCase{
+		{`"foo"`, "foo"},
+		{`1.5`, 1.5},
+		{`{"X":1,"Y":2}`, point{1, 2}},
+		{`[1,2]`, []int{1, 2}},
+	} {
+		t.Run(tc.in, func(t *testing.T) {
+			var v Variant[shapes]
+			err := json.Unmarshal([]byte(tc.in), &v)
+			if err != nil {
+				panic(err)
+			}
+			if !reflect.DeepEqual(v.Value(), tc.expected) || v.Type() != reflect.TypeOf(tc.expected) {
+				t.Errorf("not equal: expected(%#v) != actual(%#v)", tc.expected, v.Value())
+			}
+			bin, err := json.Marshal(v)
+			if err != nil {
+				panic(err)
+			}
+			if string(bin) != tc.in {
+				t.Errorf("not equal: expected(%s) != actual(%s)", tc.in, string(bin))
+			}
+		})
+	}
+
+	var v Variant[shapes]
+	// only []int is tried and its error is returned as is.
+	err := json.Unmarshal([]byte(`["a"]`), &v)
+	if err == nil || errors.Is(err, ErrNoVariant) {
+		t.Errorf("should fail only for []int, but is %v", err)
+	}
+	t.Logf("err = %v", err)
+	// variant_test.go:190: err = json: cannot unmarshal JSON string into Go int within "/0"
+	err = json.Unmarshal([]byte(`true`), &v)
+	if !errors.Is(err, ErrNoVariant) {
+		t.Errorf("should be ErrNoVariant, but is %v", err)
+	}
+
+	// candidates with the same hint are tried in order.
+	type objects struct{}
+	RegisterOrdered[objects, point]('{')
+	RegisterOrdered[objects, named]('{')
+	RegisterOrdered[objects, any](0)
+	for _, tc := range []testCase{
+		{`{"X":1}`, point{X: 1}},
+		{`{"Name":"foo"}`, named{"foo"}},
+		{`{"Z":true}`, map[string]any{"Z": true}},
+		{`false`, false},
+	} {
+		t.Run(tc.in, func(t *testing.T) {
+			var o Variant[objects]
+			err := json.Unmarshal([]byte(tc.in), &o, json.RejectUnknownMembers(true))
+			if err != nil {
+				panic(err)
+			}
+			if !reflect.DeepEqual(o.Value(), tc.expected) {
+				t.Errorf("not equal: expected(%#v) != actual(%#v)", tc.expected, o.Value())
+			}
+			if n, ok := VariantAs[named](o); ok != (o.Type() == reflect.TypeFor[named]()) {
+				t.Errorf("incorrect: %#v, %t", n, ok)
+			}
+		})
+	}
+}